@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"log"
+)
+
+// blocksCmd represents the blocks command
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Shows block authors and gaps for a round",
+	Run: func(cmd *cobra.Command, args []string) {
+		round, _ := cmd.Flags().GetUint32("round")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		summary, _ := cmd.Flags().GetBool("summary")
+		chain, _ := cmd.Root().Flags().GetString("chain")
+		// Round boundaries are computed from head
+		c, err := client.NewClient(config.GetChainConfig(chain, 0, 0))
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("Fetching blocks for round %v\n", round)
+		data, err := c.FetchRoundBlocks(config.BlocksScanConfig{
+			Round:       round,
+			Concurrency: concurrency,
+		})
+		if err != nil {
+			panic(err)
+		}
+		render(cmd, display.BlocksDocument(data, c, getTableOptions(cmd), summary), "table")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(blocksCmd)
+	blocksScanConfig := config.DefaultBlocksScanConfig()
+	blocksCmd.PersistentFlags().Uint32(
+		"round",
+		blocksScanConfig.Round,
+		"Round number, defaults to current round",
+	)
+	blocksCmd.PersistentFlags().Int(
+		"concurrency",
+		blocksScanConfig.Concurrency,
+		"Number of blocks fetched in parallel",
+	)
+	blocksCmd.PersistentFlags().Bool(
+		"summary",
+		false,
+		"Only show per collator summary",
+	)
+}
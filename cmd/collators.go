@@ -6,8 +6,9 @@ import (
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/client"
 	"github.com/zooper-corp/mooncli/internal/display"
-	"github.com/zooper-corp/mooncli/internal/tools"
+	"github.com/zooper-corp/mooncli/internal/filter"
 	"log"
+	"strings"
 )
 
 // collatorsCmd represents the collators command
@@ -21,18 +22,22 @@ var collatorsTableCmd = &cobra.Command{
 	Use:   "table",
 	Short: "Shows collator pool statistics as table",
 	Run: func(cmd *cobra.Command, args []string) {
-		compact, _ := cmd.Flags().GetBool("compact")
-		sortKey, _ := cmd.Flags().GetString("sort-key")
-		sortDesc, _ := cmd.Flags().GetBool("sort-desc")
-		revokeRounds, _ := cmd.Flags().GetUint32("revoke-rounds")
-		options := config.TableOptions{
-			Compact:      compact,
-			SortKey:      sortKey,
-			SortDesc:     sortDesc,
-			RevokeRounds: revokeRounds,
+		if interval, _ := cmd.Flags().GetDuration("watch"); interval > 0 {
+			watchCollators(cmd, interval)
+			return
 		}
 		data, client := fetchPool(cmd)
-		display.DumpTable(data, client, options)
+		renderCollators(cmd, data, client, "table")
+	},
+}
+
+// collatorsReliabilityCmd represents the collators reliability command
+var collatorsReliabilityCmd = &cobra.Command{
+	Use:   "reliability",
+	Short: "Shows expected vs produced blocks across history rounds",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, client := fetchPool(cmd)
+		render(cmd, display.ReliabilityDocument(data, client, getTableOptions(cmd)), "table")
 	},
 }
 
@@ -41,17 +46,45 @@ var collatorsJsonCmd = &cobra.Command{
 	Use:   "json",
 	Short: "Dumps collator pool statistics as json",
 	Run: func(cmd *cobra.Command, args []string) {
-		type JsonData struct {
-			Client *client.Client      `json:"info"`
-			Pool   client.CollatorPool `json:"collator_pool"`
-		}
 		data, client := fetchPool(cmd)
-		fmt.Println(tools.DumpJson(JsonData{Client: client, Pool: data}))
+		renderCollators(cmd, data, client, "json")
+	},
+}
+
+// collatorsCsvCmd represents the collators csv command
+var collatorsCsvCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Dumps collator pool statistics as CSV or TSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		options := getCsvOptions(cmd)
+		data, _ := fetchPool(cmd)
+		doc, err := display.CollatorsCsvDocument(data, options)
+		if err != nil {
+			panic(err)
+		}
+		render(cmd, doc, options.Format)
 	},
 }
 
+func renderCollators(cmd *cobra.Command, data client.CollatorPool, c *client.Client, defaultFormat string) {
+	doc, err := display.CollatorsDocument(data, c, getTableOptions(cmd))
+	if err != nil {
+		panic(err)
+	}
+	render(cmd, doc, defaultFormat)
+}
+
 func fetchPool(cmd *cobra.Command) (client.CollatorPool, *client.Client) {
 	c := getClient(cmd)
+	data, err := fetchPoolWithClient(cmd, c)
+	if err != nil {
+		panic(err)
+	}
+	return data, c
+}
+
+// fetchPoolWithClient fetches the filtered collator pool at the client snap point
+func fetchPoolWithClient(cmd *cobra.Command, c *client.Client) (client.CollatorPool, error) {
 	// Get the pool
 	historyRounds, _ := cmd.Flags().GetUint32("history")
 	revokes, _ := cmd.Flags().GetBool("revokes")
@@ -63,9 +96,88 @@ func fetchPool(cmd *cobra.Command) (client.CollatorPool, *client.Client) {
 		Revokes:       revokes,
 	})
 	if err != nil {
-		panic(err)
+		return data, err
 	}
-	return data, c
+	// Filter collators, revoke metrics use the table revoke rounds
+	expr, _ := cmd.Flags().GetString("filter")
+	revokeRounds := config.GetDefaultTableOptions().RevokeRounds
+	if cmd.Flags().Lookup("revoke-rounds") != nil {
+		revokeRounds, _ = cmd.Flags().GetUint32("revoke-rounds")
+	}
+	data.Collators, err = filter.Collators(data.Collators, expr, data.RoundNumber+revokeRounds)
+	return data, err
+}
+
+func getTableOptions(cmd *cobra.Command) config.TableOptions {
+	compact, _ := cmd.Flags().GetBool("compact")
+	sortKey, _ := cmd.Flags().GetString("sort-key")
+	sortDesc, _ := cmd.Flags().GetBool("sort-desc")
+	revokeRounds, _ := cmd.Flags().GetUint32("revoke-rounds")
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	sort, _ := cmd.Flags().GetStringSlice("sort")
+	return config.TableOptions{
+		Compact:      compact,
+		SortKey:      sortKey,
+		SortDesc:     sortDesc,
+		RevokeRounds: revokeRounds,
+		Ascii:        ascii,
+		Columns:      columns,
+		Sort:         sort,
+	}
+}
+
+func getCsvOptions(cmd *cobra.Command) config.CsvOptions {
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(format)
+	if format != "csv" && format != "tsv" {
+		panic(fmt.Errorf("unsupported format '%v', expecting csv or tsv", format))
+	}
+	perRound, _ := cmd.Flags().GetBool("per-round")
+	revokeRounds, _ := cmd.Flags().GetUint32("revoke-rounds")
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	return config.CsvOptions{
+		Format:       format,
+		PerRound:     perRound,
+		RevokeRounds: revokeRounds,
+		Columns:      columns,
+	}
+}
+
+func addCsvFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(
+		"format",
+		config.GetDefaultCsvOptions().Format,
+		"Output format [csv,tsv]",
+	)
+}
+
+func addTableFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(
+		"compact",
+		config.GetDefaultTableOptions().Compact,
+		"Shows compact table",
+	)
+	cmd.PersistentFlags().String(
+		"sort-key",
+		config.GetDefaultTableOptions().SortKey,
+		"Sort table by column name, the column is added if not shown",
+	)
+	cmd.PersistentFlags().Bool(
+		"sort-desc",
+		config.GetDefaultTableOptions().SortDesc,
+		"Sort table in descending order",
+	)
+	cmd.PersistentFlags().StringSlice(
+		"sort",
+		[]string{},
+		"Sort table by multiple columns, prefix with '-' for descending order (rank,-counted)",
+	)
+	cmd.PersistentFlags().Uint32(
+		"revoke-rounds",
+		config.GetDefaultTableOptions().RevokeRounds,
+		"Number of rounds to show in revoke stats",
+	)
 }
 
 func init() {
@@ -96,26 +208,37 @@ func init() {
 		collatorsPoolConfig.Address,
 		"Retrieve info only for a given address",
 	)
-	collatorsCmd.AddCommand(collatorsTableCmd)
-	collatorsTableCmd.PersistentFlags().Bool(
-		"compact",
-		config.GetDefaultTableOptions().Compact,
-		"Shows compact table",
+	collatorsCmd.PersistentFlags().StringSlice(
+		"columns",
+		[]string{},
+		"Columns to show in order, use name@round for a history or revoke round (blocks@-1,counted@+7)",
 	)
-	collatorsTableCmd.PersistentFlags().String(
-		"sort-key",
-		config.GetDefaultTableOptions().SortKey,
-		"Sort table by key",
+	collatorsCmd.PersistentFlags().String(
+		"filter",
+		"",
+		fmt.Sprintf("Filter collators with an expression like 'selected && avg_blocks < 3', fields: %v",
+			strings.Join(filter.CollatorFields(), ",")),
 	)
-	collatorsTableCmd.PersistentFlags().Bool(
-		"sort-desc",
-		config.GetDefaultTableOptions().SortDesc,
-		"Sort table in descending order",
+	collatorsCmd.AddCommand(collatorsTableCmd)
+	addTableFlags(collatorsTableCmd)
+	collatorsTableCmd.PersistentFlags().Duration(
+		"watch",
+		0,
+		"Refresh the table at the given interval (e.g. 30s) highlighting changes, only at head",
+	)
+	collatorsCmd.AddCommand(collatorsJsonCmd)
+	collatorsCmd.AddCommand(collatorsReliabilityCmd)
+	addTableFlags(collatorsReliabilityCmd)
+	collatorsCmd.AddCommand(collatorsCsvCmd)
+	addCsvFlags(collatorsCsvCmd)
+	collatorsCsvCmd.PersistentFlags().Bool(
+		"per-round",
+		config.GetDefaultCsvOptions().PerRound,
+		"One row per collator per history and revoke round",
 	)
-	collatorsTableCmd.PersistentFlags().Uint32(
+	collatorsCsvCmd.PersistentFlags().Uint32(
 		"revoke-rounds",
-		config.GetDefaultTableOptions().RevokeRounds,
-		"Number of rounds to show in revoke stats",
+		config.GetDefaultCsvOptions().RevokeRounds,
+		"Number of rounds used for revoke columns",
 	)
-	collatorsCmd.AddCommand(collatorsJsonCmd)
 }
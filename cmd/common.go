@@ -4,6 +4,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"io"
+	"os"
 )
 
 func getClient(cmd *cobra.Command) *client.Client {
@@ -16,3 +19,23 @@ func getClient(cmd *cobra.Command) *client.Client {
 	}
 	return c
 }
+
+// render writes a document to stdout using the --output format or the command default
+func render(cmd *cobra.Command, doc display.Document, defaultFormat string) {
+	renderTo(cmd, os.Stdout, doc, defaultFormat)
+}
+
+func renderTo(cmd *cobra.Command, w io.Writer, doc display.Document, defaultFormat string) {
+	format, _ := cmd.Flags().GetString("output")
+	if format == "" {
+		format = defaultFormat
+	}
+	renderer, err := display.NewRenderer(format)
+	if err != nil {
+		panic(err)
+	}
+	err = renderer.Render(w, doc)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"log"
+	"strings"
+)
+
+// delegationsCmd represents the delegations command
+var delegationsCmd = &cobra.Command{
+	Use:   "delegations",
+	Short: "Shows delegations of the collator pool",
+}
+
+// delegationsCsvCmd represents the delegations csv command
+var delegationsCsvCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Dumps delegations as CSV or TSV, one row per delegation",
+	Run: func(cmd *cobra.Command, args []string) {
+		data := fetchDelegations(cmd)
+		render(cmd, display.DelegationsDocument(data), getCsvOptions(cmd).Format)
+	},
+}
+
+// fetchDelegations returns the collator pool with delegations filtered by collator or delegator address
+func fetchDelegations(cmd *cobra.Command) client.CollatorPool {
+	c := getClient(cmd)
+	address, _ := cmd.Flags().GetString("address")
+	log.Printf("Fetching delegations for %v\n", address)
+	data, err := c.FetchCollatorPool(config.CollatorsPoolConfig{
+		HistoryRounds: 0,
+		Revokes:       true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if address == "" {
+		return data
+	}
+	for i, collator := range data.Collators {
+		if strings.EqualFold(collator.Address, address) {
+			continue
+		}
+		filtered := make([]client.DelegatorState, 0)
+		for _, delegation := range collator.Delegations {
+			if strings.EqualFold(delegation.Address, address) {
+				filtered = append(filtered, delegation)
+			}
+		}
+		data.Collators[i].Delegations = filtered
+	}
+	return data
+}
+
+func init() {
+	rootCmd.AddCommand(delegationsCmd)
+	delegationsCmd.PersistentFlags().Int64(
+		"block",
+		0,
+		"Absolute block or position relative to the round",
+	)
+	delegationsCmd.PersistentFlags().Uint32(
+		"round",
+		0,
+		"Round number, when used block will be relative",
+	)
+	delegationsCmd.PersistentFlags().String(
+		"address",
+		"",
+		"Only show delegations of a given collator or delegator",
+	)
+	delegationsCmd.AddCommand(delegationsCsvCmd)
+	addCsvFlags(delegationsCsvCmd)
+}
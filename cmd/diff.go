@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"log"
+	"strconv"
+	"time"
+)
+
+// collatorsDiffCmd represents the collators diff command
+var collatorsDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Shows what changed in the collator pool between two rounds, blocks or times",
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := getDiffTarget(cmd, "from")
+		if err != nil {
+			panic(err)
+		}
+		to, err := getDiffTarget(cmd, "to")
+		if err != nil {
+			panic(err)
+		}
+		if from == (config.SnapConfig{}) {
+			panic(fmt.Errorf("one of --from-round, --from-block or --from-time is required"))
+		}
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		address, _ := cmd.Flags().GetString("address")
+		chain, _ := cmd.Root().Flags().GetString("chain")
+		// One connection is moved across both snap points
+		c, err := client.NewClient(config.GetChainConfig(chain, 0, 0))
+		if err != nil {
+			panic(err)
+		}
+		fetch := func(target config.SnapConfig) (client.CollatorPool, client.DiffPoint) {
+			if err := c.MoveSnap(target); err != nil {
+				panic(err)
+			}
+			log.Printf("Fetching collator pool at block %v\n", c.SnapBlock.Number)
+			pool, err := c.FetchCollatorPool(config.CollatorsPoolConfig{
+				Address: address,
+				Revokes: true,
+			})
+			if err != nil {
+				panic(err)
+			}
+			return pool, client.DiffPoint{Round: c.SnapRound, Block: c.SnapBlock}
+		}
+		fromPool, fromPoint := fetch(from)
+		toPool, toPoint := fetch(to)
+		if fromPoint.Block.Number > toPoint.Block.Number {
+			panic(fmt.Errorf("from block %v is after to block %v", fromPoint.Block.Number, toPoint.Block.Number))
+		}
+		diff := client.DiffPools(fromPool, toPool, config.DiffConfig{DelegationThreshold: threshold})
+		diff.From, diff.To = fromPoint, toPoint
+		render(cmd, display.DiffDocument(diff, c.Chain, getTableOptions(cmd)), "table")
+	},
+}
+
+// getDiffTarget returns the snap point of one side of the diff, an empty target is the chain head
+func getDiffTarget(cmd *cobra.Command, side string) (config.SnapConfig, error) {
+	round, _ := cmd.Flags().GetUint32(side + "-round")
+	block, _ := cmd.Flags().GetInt64(side + "-block")
+	ts, _ := cmd.Flags().GetString(side + "-time")
+	target := config.SnapConfig{TargetBlock: block, TargetRound: round}
+	if ts != "" {
+		if round != 0 || block != 0 {
+			return target, fmt.Errorf("--%v-time cannot be used with round or block", side)
+		}
+		t, err := parseTime(ts)
+		if err != nil {
+			return target, err
+		}
+		target.TargetTime = t
+	}
+	return target, nil
+}
+
+// parseTime accepts RFC3339, dates with optional minutes in UTC and unix seconds
+func parseTime(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%v', expecting RFC3339, 'YYYY-MM-DD [HH:MM]' or unix seconds", s)
+}
+
+func init() {
+	collatorsCmd.AddCommand(collatorsDiffCmd)
+	for _, side := range []string{"from", "to"} {
+		collatorsDiffCmd.PersistentFlags().Uint32(
+			side+"-round",
+			0,
+			fmt.Sprintf("Round number of the %v point, when used block will be relative", side),
+		)
+		collatorsDiffCmd.PersistentFlags().Int64(
+			side+"-block",
+			0,
+			fmt.Sprintf("Absolute block of the %v point or position relative to the round", side),
+		)
+		collatorsDiffCmd.PersistentFlags().String(
+			side+"-time",
+			"",
+			fmt.Sprintf("Time of the %v point (2022-05-01 12:00 UTC, RFC3339 or unix seconds)", side),
+		)
+	}
+	collatorsDiffCmd.PersistentFlags().Float64(
+		"threshold",
+		config.DefaultDiffConfig().DelegationThreshold,
+		"Minimum amount for new, removed or changed top delegations to be reported",
+	)
+	collatorsDiffCmd.PersistentFlags().Bool(
+		"compact",
+		config.GetDefaultTableOptions().Compact,
+		"Shows compact table",
+	)
+}
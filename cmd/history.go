@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/internal/cache"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"log"
+	"strings"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history <collator>",
+	Short: "Shows per round rank, counted stake, blocks and delegations of a collator",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		address := args[0]
+		rounds, _ := cmd.Flags().GetUint32("rounds")
+		metric, _ := cmd.Flags().GetString("metric")
+		height, _ := cmd.Flags().GetInt("chart-height")
+		c := getClient(cmd)
+		setHistoryCache(cmd, c)
+		info, err := c.FetchAccountInfo(address)
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("Fetching %v history rounds for %v\n", rounds, address)
+		history, err := c.FetchCollatorHistory(address, rounds)
+		if err != nil {
+			panic(err)
+		}
+		options := getTableOptions(cmd)
+		doc := display.HistoryDocument(address, info.Identity.Display, history, options)
+		// The chart is only shown with the default terminal output
+		if output, _ := cmd.Flags().GetString("output"); output == "" && metric != "" {
+			chart, err := display.HistoryChart(history, metric, height, options)
+			if err != nil {
+				panic(err)
+			}
+			fmt.Println(chart)
+		}
+		render(cmd, doc, "table")
+	},
+}
+
+// setHistoryCache enables the persistent history cache unless disabled, cache errors only disable caching
+func setHistoryCache(cmd *cobra.Command, c *client.Client) {
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		return
+	}
+	path, _ := cmd.Flags().GetString("cache-dir")
+	if path == "" {
+		var err error
+		if path, err = cache.DefaultPath(); err != nil {
+			log.Printf("Unable to find cache folder, history will not be cached: %v\n", err)
+			return
+		}
+	}
+	d, err := cache.NewDisk(path)
+	if err != nil {
+		log.Printf("Unable to open cache %v, history will not be cached: %v\n", path, err)
+		return
+	}
+	c.SetHistoryCache(d)
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.PersistentFlags().Int64(
+		"block",
+		0,
+		"Absolute block or position relative to the round",
+	)
+	historyCmd.PersistentFlags().Uint32(
+		"round",
+		0,
+		"Round number, when used block will be relative",
+	)
+	historyCmd.PersistentFlags().Uint32(
+		"rounds",
+		28,
+		"Number of rounds to fetch",
+	)
+	historyCmd.PersistentFlags().String(
+		"metric",
+		"rank",
+		fmt.Sprintf("Metric to chart [%v], empty to only show the table", strings.Join(display.HistoryMetrics, ",")),
+	)
+	historyCmd.PersistentFlags().Int(
+		"chart-height",
+		10,
+		"Chart height in lines",
+	)
+	historyCmd.PersistentFlags().Bool(
+		"compact",
+		false,
+		"Shows compact table",
+	)
+	historyCmd.PersistentFlags().Bool(
+		"no-cache",
+		false,
+		"Do not read or write the persistent history cache",
+	)
+	historyCmd.PersistentFlags().String(
+		"cache-dir",
+		"",
+		"History cache folder, defaults to mooncli in the user cache folder",
+	)
+}
@@ -1,21 +1,15 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/async"
 	"github.com/zooper-corp/mooncli/internal/client"
-	"github.com/zooper-corp/mooncli/internal/tools"
+	"github.com/zooper-corp/mooncli/internal/display"
 	"log"
 	"sync"
 )
 
-type infoResult struct {
-	Metadata *client.Client       `json:"info"`
-	Accounts []client.AccountInfo `json:"accounts,omitempty"`
-}
-
 // infoCmd represents the info command
 var infoCmd = &cobra.Command{
 	Use:   "info",
@@ -49,12 +43,7 @@ var infoCmd = &cobra.Command{
 				accounts = append(accounts, r.Value)
 			}
 		}
-		// Build result
-		result := infoResult{
-			Metadata: c,
-			Accounts: accounts,
-		}
-		fmt.Println(tools.DumpJson(result))
+		render(cmd, display.InfoDocument(c, accounts, getTableOptions(cmd)), "json")
 	},
 }
 
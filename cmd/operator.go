@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"os"
+)
+
+// operatorCmd represents the operator command
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Tools for collator operators",
+}
+
+// operatorCheckCmd represents the operator check command
+var operatorCheckCmd = &cobra.Command{
+	Use:   "check <address>",
+	Short: "Checks collator author mapping and block production, exits with 1 on issues",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		minRoundBlocks, _ := cmd.Flags().GetUint32("min-round-blocks")
+		c := getClient(cmd)
+		report, err := c.CheckOperator(config.OperatorCheckConfig{
+			Address:        args[0],
+			MinRoundBlocks: minRoundBlocks,
+		})
+		if err != nil {
+			panic(err)
+		}
+		render(cmd, display.OperatorDocument(report, getTableOptions(cmd)), "json")
+		if !report.Healthy() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(operatorCmd)
+	operatorCmd.PersistentFlags().Int64(
+		"block",
+		0,
+		"Absolute block or position relative to the round",
+	)
+	operatorCmd.PersistentFlags().Uint32(
+		"round",
+		0,
+		"Round number, when used block will be relative",
+	)
+	operatorCmd.AddCommand(operatorCheckCmd)
+	operatorCheckCmd.PersistentFlags().Uint32(
+		"min-round-blocks",
+		config.DefaultOperatorCheckConfig().MinRoundBlocks,
+		"Blocks elapsed in the round before a selected collator with no blocks is flagged",
+	)
+}
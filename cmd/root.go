@@ -32,6 +32,17 @@ func initLog(cmd *cobra.Command, args []string) {
 
 func init() {
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "show logs")
+	rootCmd.PersistentFlags().Bool(
+		"ascii",
+		false,
+		"Only use ASCII characters in tables, names are transliterated",
+	)
+	rootCmd.PersistentFlags().StringP(
+		"output",
+		"o",
+		"",
+		"Output format [json,yaml,table,csv,tsv,markdown], default depends on the command",
+	)
 	rootCmd.PersistentFlags().String(
 		"chain",
 		"moonbeam",
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/server"
@@ -16,21 +17,73 @@ var serveCmd = &cobra.Command{
 	Short: "Serve collator and delegator data JSON via API server",
 	Run: func(cmd *cobra.Command, args []string) {
 		runtime.GOMAXPROCS(1)
-		chain, _ := cmd.Root().Flags().GetString("chain")
-		interval, _ := cmd.Flags().GetUint32("interval")
-		listen, _ := cmd.Flags().GetString("listen")
-		dataPath, _ := cmd.Flags().GetString("data-path")
-		httpConfig := config.HttpConfig{
-			Addr:           listen,
-			UpdateInterval: time.Duration(interval) * time.Second,
-			ChainConfig:    config.GetChainConfig(chain, 0, 0),
-			DataPath:       dataPath,
+		httpConfig, err := getHttpConfig(cmd)
+		if err != nil {
+			panic(err)
+		}
+		// The config file is read again on SIGHUP
+		var reload func() (config.HttpConfig, error)
+		if path, _ := cmd.Flags().GetString("config"); path != "" {
+			reload = func() (config.HttpConfig, error) {
+				log.Printf("Reloading configuration from %v", path)
+				return getHttpConfig(cmd)
+			}
 		}
 		log.Printf("Starting API server %v", tools.DumpJson(httpConfig))
-		server.ServeChainData(httpConfig)
+		server.ServeChainData(httpConfig, reload)
 	},
 }
 
+// getHttpConfig reads the serve flags, then applies the optional config file
+func getHttpConfig(cmd *cobra.Command) (config.HttpConfig, error) {
+	chain, _ := cmd.Root().Flags().GetString("chain")
+	interval, _ := cmd.Flags().GetUint32("interval")
+	listen, _ := cmd.Flags().GetString("listen")
+	dataPath, _ := cmd.Flags().GetString("data-path")
+	archiveRounds, _ := cmd.Flags().GetUint32("archive-rounds")
+	compactAfter, _ := cmd.Flags().GetUint32("archive-compact-after")
+	follow, _ := cmd.Flags().GetString("follow")
+	followInterval, _ := cmd.Flags().GetUint32("follow-interval")
+	readTimeout, _ := cmd.Flags().GetUint32("read-timeout")
+	writeTimeout, _ := cmd.Flags().GetUint32("write-timeout")
+	idleTimeout, _ := cmd.Flags().GetUint32("idle-timeout")
+	shutdownTimeout, _ := cmd.Flags().GetUint32("shutdown-timeout")
+	configPath, _ := cmd.Flags().GetString("config")
+	if follow != "" && dataPath != "" {
+		return config.HttpConfig{}, fmt.Errorf("--follow and --data-path cannot be used together")
+	}
+	networks, err := config.GetNetworkConfigs(chain)
+	if err != nil {
+		return config.HttpConfig{}, err
+	}
+	httpConfig := config.HttpConfig{
+		Addr:           listen,
+		UpdateInterval: time.Duration(interval) * time.Second,
+		Networks:       networks,
+		DataPath:       dataPath,
+		Archive: config.ArchiveConfig{
+			Rounds:       archiveRounds,
+			CompactAfter: compactAfter,
+		},
+		FollowPath:      follow,
+		FollowInterval:  time.Duration(followInterval) * time.Second,
+		ReadTimeout:     time.Duration(readTimeout) * time.Second,
+		WriteTimeout:    time.Duration(writeTimeout) * time.Second,
+		IdleTimeout:     time.Duration(idleTimeout) * time.Second,
+		ShutdownTimeout: time.Duration(shutdownTimeout) * time.Second,
+	}
+	if configPath != "" {
+		f, err := config.LoadServeFile(configPath)
+		if err != nil {
+			return config.HttpConfig{}, err
+		}
+		if err := f.Apply(&httpConfig); err != nil {
+			return config.HttpConfig{}, err
+		}
+	}
+	return httpConfig, nil
+}
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 	httpConfig := config.GetDefaultHttpConfig()
@@ -49,4 +102,41 @@ func init() {
 		"",
 		"An optional data path, if provided update data will be cached there",
 	)
+	serveCmd.PersistentFlags().Uint32(
+		"archive-rounds",
+		httpConfig.Archive.Rounds,
+		"Number of rounds kept in the data path archive, 0 to disable",
+	)
+	serveCmd.PersistentFlags().Uint32(
+		"archive-compact-after",
+		httpConfig.Archive.CompactAfter,
+		"Archived rounds older than this drop the collator history, 0 to keep it",
+	)
+	serveCmd.PersistentFlags().String(
+		"follow",
+		"",
+		"Data path of a writer instance to serve, the server will not connect to the chain",
+	)
+	serveCmd.PersistentFlags().Uint32(
+		"follow-interval",
+		uint32(httpConfig.FollowInterval.Seconds()),
+		"Seconds between checks of the followed data path",
+	)
+	serveCmd.PersistentFlags().String(
+		"config",
+		"",
+		"Optional YAML file with interval, endpoints and archive settings, read again on SIGHUP",
+	)
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+		usage string
+	}{
+		{"read-timeout", httpConfig.ReadTimeout, "Seconds allowed to read a request"},
+		{"write-timeout", httpConfig.WriteTimeout, "Seconds allowed to handle a request, event streams excluded"},
+		{"idle-timeout", httpConfig.IdleTimeout, "Seconds keep-alive connections are kept idle"},
+		{"shutdown-timeout", httpConfig.ShutdownTimeout, "Seconds allowed to drain requests and updates on exit"},
+	} {
+		serveCmd.PersistentFlags().Uint32(timeout.name, uint32(timeout.value.Seconds()), timeout.usage+", 0 to disable")
+	}
 }
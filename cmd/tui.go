@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/filter"
+	"github.com/zooper-corp/mooncli/internal/tui"
+	"log"
+	"strings"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive full screen explorer for collators and delegations",
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshot := loadSnapshot(cmd)
+		if path, _ := cmd.Flags().GetString("save"); path != "" {
+			if err := snapshot.Save(path); err != nil {
+				panic(err)
+			}
+			fmt.Printf("Snapshot of %v collators saved to %v\n", len(snapshot.Pool.Collators), path)
+			return
+		}
+		options := getTableOptions(cmd)
+		model := tui.NewModel(snapshot, options.RevokeRounds)
+		expr, _ := cmd.Flags().GetString("filter")
+		if err := model.SetFilter(expr); err != nil {
+			panic(err)
+		}
+		if err := tui.NewApp(model, options).Run(); err != nil {
+			panic(err)
+		}
+	},
+}
+
+// loadSnapshot reads the --snapshot file or fetches the pool with delegations from the chain
+func loadSnapshot(cmd *cobra.Command) tui.Snapshot {
+	if path, _ := cmd.Flags().GetString("snapshot"); path != "" {
+		log.Printf("Loading snapshot from %v\n", path)
+		snapshot, err := tui.LoadSnapshot(path)
+		if err != nil {
+			panic(err)
+		}
+		return snapshot
+	}
+	c := getClient(cmd)
+	historyRounds, _ := cmd.Flags().GetUint32("history")
+	fmt.Printf("Fetching collator pool from %v...\n", c.RpcUrl)
+	pool, err := c.FetchCollatorPool(config.CollatorsPoolConfig{
+		HistoryRounds: historyRounds,
+		Revokes:       true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return tui.NewSnapshot(pool, c)
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.PersistentFlags().Int64(
+		"block",
+		0,
+		"Absolute block or position relative to the round",
+	)
+	tuiCmd.PersistentFlags().Uint32(
+		"round",
+		0,
+		"Round number, when used block will be relative",
+	)
+	tuiCmd.PersistentFlags().Uint32(
+		"history",
+		config.DefaultCollatorsPoolConfig().HistoryRounds,
+		"Number of rounds to fetch points history",
+	)
+	tuiCmd.PersistentFlags().Uint32(
+		"revoke-rounds",
+		config.GetDefaultTableOptions().RevokeRounds,
+		"Number of rounds used for the revoke delta",
+	)
+	tuiCmd.PersistentFlags().String(
+		"filter",
+		"",
+		fmt.Sprintf("Initial filter expression, fields: %v", strings.Join(filter.CollatorFields(), ",")),
+	)
+	tuiCmd.PersistentFlags().String(
+		"snapshot",
+		"",
+		"Browse a saved snapshot offline, the output of 'collators json' is accepted without delegations",
+	)
+	tuiCmd.PersistentFlags().String(
+		"save",
+		"",
+		"Save a snapshot including delegations to a file and exit",
+	)
+}
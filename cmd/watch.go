@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/async"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// watchCollators redraws the collator table at every interval, cells changed since the previous refresh are
+// highlighted, the same connection is kept and moved to the new head on each refresh
+func watchCollators(cmd *cobra.Command, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	chain, _ := cmd.Root().Flags().GetString("chain")
+	c, err := client.NewClient(config.GetChainConfig(chain, 0, 0))
+	if err != nil {
+		panic(err)
+	}
+	options := getTableOptions(cmd)
+	var previous display.Table
+	var previousKeys []string
+	for refresh := 0; ; refresh++ {
+		// Fetch in background so that Ctrl-C is handled while waiting for the chain
+		ch := make(chan async.Result[client.CollatorPool], 1)
+		go func(update bool) {
+			if update {
+				if err := c.UpdateSnap(); err != nil {
+					ch <- async.ErrorResult[client.CollatorPool](err)
+					return
+				}
+			}
+			ch <- async.ResultFrom(fetchPoolWithClient(cmd, c))
+		}(refresh > 0)
+		var r async.Result[client.CollatorPool]
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return
+		case r = <-ch:
+		}
+		if r.IsErr() {
+			log.Printf("Unable to refresh %v\n", r.Err)
+			fmt.Printf("Refresh failed at %v: %v\n", time.Now().Format("15:04:05"), r.Err)
+		} else {
+			doc, err := display.CollatorsDocument(r.Value, c, options)
+			if err != nil {
+				panic(err)
+			}
+			keys := make([]string, 0, len(r.Value.Collators))
+			for _, info := range r.Value.Collators {
+				keys = append(keys, info.Address)
+			}
+			// Keep a copy of the plain rows for the next comparison
+			current := doc.Tables[0]
+			current.Rows = copyRows(current.Rows)
+			changes := display.HighlightChanges(&doc.Tables[0], keys, previous, previousKeys)
+			previous, previousKeys = current, keys
+			doc.Title = fmt.Sprintf(
+				"%v\nUpdated %v, %v changes, next refresh in %v (Ctrl-C to exit)",
+				doc.Title,
+				time.Now().Format("15:04:05"),
+				changes,
+				interval,
+			)
+			var out bytes.Buffer
+			renderTo(cmd, &out, doc, "table")
+			fmt.Print(clearScreen)
+			_, _ = out.WriteTo(os.Stdout)
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// copyRows returns a copy of the rows as highlighting replaces changed values
+func copyRows(rows [][]interface{}) [][]interface{} {
+	result := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, append([]interface{}{}, row...))
+	}
+	return result
+}
@@ -0,0 +1,13 @@
+package config
+
+type BlocksScanConfig struct {
+	Round       uint32
+	Concurrency int
+}
+
+func DefaultBlocksScanConfig() BlocksScanConfig {
+	return BlocksScanConfig{
+		Round:       0,
+		Concurrency: 16,
+	}
+}
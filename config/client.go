@@ -26,6 +26,8 @@ type ChainConfig struct {
 type SnapConfig struct {
 	TargetBlock int64
 	TargetRound uint32
+	// TargetTime selects the last block produced at or before a given time, block and round are ignored
+	TargetTime time.Time
 }
 
 // MinCacheTTL Duration the minimum time an object is valid
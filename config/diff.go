@@ -0,0 +1,12 @@
+package config
+
+type DiffConfig struct {
+	// DelegationThreshold is the minimum amount change for a delegation to be reported
+	DelegationThreshold float64
+}
+
+func DefaultDiffConfig() DiffConfig {
+	return DiffConfig{
+		DelegationThreshold: 1000,
+	}
+}
@@ -1,10 +1,8 @@
 package config
 
 import (
-	"github.com/jedib0t/go-pretty/v6/table"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
-	"strings"
 )
 
 type TableOptions struct {
@@ -12,6 +10,11 @@ type TableOptions struct {
 	SortKey      string
 	SortDesc     bool
 	RevokeRounds uint32
+	Ascii        bool
+	// Columns lists the columns to show in order, empty for defaults
+	Columns []string
+	// Sort lists sort keys by priority, a leading '-' sorts in descending order
+	Sort []string
 }
 
 func GetDefaultTableOptions() TableOptions {
@@ -20,6 +23,7 @@ func GetDefaultTableOptions() TableOptions {
 		SortKey:      "Rank",
 		SortDesc:     false,
 		RevokeRounds: 28,
+		Ascii:        false,
 	}
 }
 
@@ -27,6 +31,17 @@ func (to *TableOptions) GetSortKey() string {
 	return cases.Title(language.Und).String(to.SortKey)
 }
 
+// GetSortKeys returns the sort keys, falling back to the single sort key and order
+func (to *TableOptions) GetSortKeys() []string {
+	if len(to.Sort) > 0 {
+		return to.Sort
+	}
+	if to.SortDesc {
+		return []string{"-" + to.GetSortKey()}
+	}
+	return []string{to.GetSortKey()}
+}
+
 func (to *TableOptions) GetTableWidth() int {
 	if to.Compact {
 		return 80
@@ -35,18 +50,17 @@ func (to *TableOptions) GetTableWidth() int {
 	}
 }
 
-func (to *TableOptions) GetSortMode() table.SortMode {
-	if !strings.EqualFold(to.SortKey, "display") {
-		if to.SortDesc {
-			return table.DscNumeric
-		} else {
-			return table.AscNumeric
-		}
-	} else {
-		if to.SortDesc {
-			return table.Dsc
-		} else {
-			return table.Dsc
-		}
+type CsvOptions struct {
+	Format       string
+	PerRound     bool
+	RevokeRounds uint32
+	Columns      []string
+}
+
+func GetDefaultCsvOptions() CsvOptions {
+	return CsvOptions{
+		Format:       "csv",
+		PerRound:     false,
+		RevokeRounds: GetDefaultTableOptions().RevokeRounds,
 	}
 }
@@ -0,0 +1,14 @@
+package config
+
+type OperatorCheckConfig struct {
+	Address string
+	// MinRoundBlocks blocks that must be elapsed in the round before flagging a collator with no blocks
+	MinRoundBlocks uint32
+}
+
+func DefaultOperatorCheckConfig() OperatorCheckConfig {
+	return OperatorCheckConfig{
+		Address:        "",
+		MinRoundBlocks: 300,
+	}
+}
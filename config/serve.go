@@ -1,19 +1,151 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
 
 type HttpConfig struct {
 	Addr           string
 	UpdateInterval time.Duration
-	ChainConfig    ChainConfig
-	DataPath       string
+	// Networks are served under /{name}, a single network is also served at the root
+	Networks []NetworkConfig
+	// DataPath is shared by networks, each one using a sub folder named after it when more than one is served
+	DataPath string
+	Archive  ArchiveConfig
+	// FollowPath is the data path of a writer instance, when set the server never connects to the chain, sub folders
+	// are used as for DataPath
+	FollowPath string
+	// FollowInterval is how often the followed manifest is checked for changes
+	FollowInterval time.Duration
+	// ReadTimeout limits reading a request, WriteTimeout handling it (event streams excluded), IdleTimeout keep-alive
+	// connections and ShutdownTimeout draining requests and in-flight updates on exit, 0 disables a timeout
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// ServeFile is the optional serve configuration file, it overrides flags and is read again on SIGHUP
+type ServeFile struct {
+	// IntervalSecs is the update interval
+	IntervalSecs uint32 `yaml:"interval"`
+	// Endpoints replaces the RPC endpoints of served networks
+	Endpoints map[string][]string `yaml:"endpoints"`
+	Archive   struct {
+		Rounds       *uint32 `yaml:"rounds"`
+		CompactAfter *uint32 `yaml:"compact_after"`
+	} `yaml:"archive"`
+}
+
+type NetworkConfig struct {
+	Name        string
+	ChainConfig ChainConfig
+}
+
+// ArchiveConfig controls the per round snapshots kept in the data path
+type ArchiveConfig struct {
+	// Rounds is the number of most recent rounds kept, 0 disables the archive
+	Rounds uint32
+	// CompactAfter is the age in rounds after which snapshots drop the collator history
+	CompactAfter uint32
 }
 
 func GetDefaultHttpConfig() HttpConfig {
 	return HttpConfig{
 		Addr:           "127.0.0.1:8080",
 		UpdateInterval: 15 * time.Minute,
-		ChainConfig:    GetDefaultChainConfig(),
-		DataPath:       "",
+		Networks: []NetworkConfig{
+			{Name: "moonbeam", ChainConfig: GetDefaultChainConfig()},
+		},
+		DataPath:        "",
+		FollowInterval:  10 * time.Second,
+		ReadTimeout:     10 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+		Archive: ArchiveConfig{
+			Rounds:       84,
+			CompactAfter: 4,
+		},
+	}
+}
+
+// GetNetworkConfigs parses a comma separated list of network names or name=endpoint pairs, a single endpoint
+// without name is named after its host
+func GetNetworkConfigs(chains string) ([]NetworkConfig, error) {
+	items := strings.Split(chains, ",")
+	result := make([]NetworkConfig, 0, len(items))
+	names := make(map[string]bool)
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		name, endpoint := item, item
+		if i := strings.Index(item, "="); i >= 0 {
+			name, endpoint = item[:i], item[i+1:]
+		} else if strings.Contains(item, "://") {
+			if len(items) > 1 {
+				return nil, fmt.Errorf("endpoint '%v' needs a name when serving several networks, use name=%v", item, item)
+			}
+			u, err := url.Parse(item)
+			if err != nil {
+				return nil, err
+			}
+			name = strings.ReplaceAll(u.Hostname(), ".", "-")
+		}
+		name = strings.ToLower(name)
+		if !networkNamePattern.MatchString(name) || name == "networks" {
+			return nil, fmt.Errorf("invalid network name '%v'", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("network '%v' listed twice", name)
+		}
+		names[name] = true
+		result = append(result, NetworkConfig{Name: name, ChainConfig: GetChainConfig(endpoint, 0, 0)})
+	}
+	return result, nil
+}
+
+func LoadServeFile(path string) (ServeFile, error) {
+	var f ServeFile
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return f, fmt.Errorf("invalid serve config %v: %v", path, err)
+	}
+	return f, nil
+}
+
+// Apply overrides the http config with the values set in the file
+func (f ServeFile) Apply(c *HttpConfig) error {
+	if f.IntervalSecs > 0 {
+		c.UpdateInterval = time.Duration(f.IntervalSecs) * time.Second
+	}
+	for name, endpoints := range f.Endpoints {
+		found := false
+		for i := range c.Networks {
+			if c.Networks[i].Name == name && len(endpoints) > 0 {
+				c.Networks[i].ChainConfig.Endpoints = endpoints
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("endpoints set for network '%v' which is not served or without urls", name)
+		}
+	}
+	if f.Archive.Rounds != nil {
+		c.Archive.Rounds = *f.Archive.Rounds
+	}
+	if f.Archive.CompactAfter != nil {
+		c.Archive.CompactAfter = *f.Archive.CompactAfter
 	}
+	return nil
 }
@@ -6,11 +6,15 @@ require (
 	github.com/NYTimes/gziphandler v1.1.1
 	github.com/OrlovEvgeny/go-mcache v0.0.0-20200121124330-1a8195b34f3a
 	github.com/centrifuge/go-substrate-rpc-client/v4 v4.0.0
+	github.com/gdamore/tcell/v2 v2.5.1
 	github.com/itering/scale.go v1.1.55
 	github.com/jedib0t/go-pretty/v6 v6.3.1
+	github.com/mattn/go-runewidth v0.0.13
+	github.com/rivo/tview v0.0.0-20220610163003-691f46d6f500
 	github.com/spf13/cobra v1.4.0
 	golang.org/x/exp v0.0.0-20220407100705-7b9b53b0aca4
 	golang.org/x/text v0.3.7
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -20,13 +24,14 @@ require (
 	github.com/decred/base58 v1.0.3 // indirect
 	github.com/decred/dcrd/crypto/blake256 v1.0.0 // indirect
 	github.com/ethereum/go-ethereum v1.10.17 // indirect
+	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/go-stack/stack v1.8.1 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/gtank/merlin v0.1.1 // indirect
 	github.com/gtank/ristretto255 v0.1.2 // indirect
 	github.com/huandu/xstrings v1.3.2 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	github.com/mattn/go-runewidth v0.0.13 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mimoo/StrobeGo v0.0.0-20181016162300-f8f6d4d2b643 // indirect
 	github.com/pierrec/xxHash v0.1.5 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
@@ -36,5 +41,6 @@ require (
 	github.com/vedhavyas/go-subkey v1.0.2 // indirect
 	golang.org/x/crypto v0.0.0-20220331220935-ae2d96664a29 // indirect
 	golang.org/x/sys v0.0.0-20220406163625-3f8b81556e12 // indirect
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d // indirect
 	gopkg.in/natefinch/npipe.v2 v2.0.0-20160621034901-c1b8fa8bdcce // indirect
 )
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9_.-]+(/[a-z0-9_.-]+)*$`)
+
+// Disk is a persistent cache of json documents stored one file per key, keys are slash separated paths
+type Disk struct {
+	path string
+}
+
+// DefaultPath returns the mooncli folder in the user cache directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mooncli"), nil
+}
+
+func NewDisk(path string) (*Disk, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return &Disk{path: path}, nil
+}
+
+// OpenDisk opens a cache without creating its folder, a missing folder has no keys
+func OpenDisk(path string) *Disk {
+	return &Disk{path: path}
+}
+
+// Get reads a key into target, returns false if the key is missing
+func (d *Disk) Get(key string, target interface{}) (bool, error) {
+	file, err := d.file(key)
+	if err != nil {
+		return false, err
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b, target); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Set writes a value, the file is replaced atomically so that readers never see partial documents
+func (d *Disk) Set(key string, value interface{}) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (d *Disk) file(key string) (string, error) {
+	if !keyPattern.MatchString(key) || strings.Contains("/"+key+"/", "/../") || strings.Contains("/"+key+"/", "/./") {
+		return "", fmt.Errorf("invalid cache key '%v'", key)
+	}
+	return filepath.Join(d.path, filepath.FromSlash(key)+".json"), nil
+}
+
+// Delete removes a key, missing keys are not an error
+func (d *Disk) Delete(key string) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the keys stored directly under a folder key
+func (d *Disk) List(folder string) ([]string, error) {
+	file, err := d.file(folder)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir(strings.TrimSuffix(file, ".json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".") {
+			result = append(result, folder+"/"+strings.TrimSuffix(name, ".json"))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestDisk(t *testing.T) {
+	d, err := NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value map[string]int
+	if ok, err := d.Get("moonbeam/history/0x01", &value); ok || err != nil {
+		t.Fatalf("expected missing key, got %v %v", ok, err)
+	}
+	if err := d.Set("moonbeam/history/0x01", map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := d.Get("moonbeam/history/0x01", &value); !ok || err != nil || value["a"] != 1 {
+		t.Fatalf("got %v %v %v", value, ok, err)
+	}
+	if keys, err := d.List("moonbeam/history"); err != nil || len(keys) != 1 || keys[0] != "moonbeam/history/0x01" {
+		t.Fatalf("unexpected keys %v %v", keys, err)
+	}
+	if err := d.Delete("moonbeam/history/0x01"); err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := d.List("moonbeam/history"); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v %v", keys, err)
+	}
+	for _, key := range []string{"../x", "a//b", "/a", "A"} {
+		if err := d.Set(key, 1); err == nil {
+			t.Errorf("expected error for key %v", key)
+		}
+	}
+}
@@ -27,7 +27,7 @@ func (c *Client) FetchAccountInfo(address string) (AccountInfo, error) {
 		identityChannel <- async.ResultFrom(c.accountIdentityFromAccount(account))
 	}()
 	// Collect
-	result := AccountInfo{}
+	result := AccountInfo{Address: address}
 	balance := <-balanceChannel
 	if balance.IsErr() {
 		return result, balance.Err
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/zooper-corp/mooncli/config"
+	"log"
+	"strings"
+)
+
+type registrationInfoUnmarshal struct {
+	Account string
+	Deposit TokenAmount
+}
+
+type AuthorMapping struct {
+	NimbusId string       `json:"nimbus_id"`
+	Account  string       `json:"account"`
+	Deposit  TokenBalance `json:"deposit"`
+}
+
+// FetchAuthorIds returns the nimbus author ids registered by an account, using the reverse
+// NimbusLookup when the runtime provides it and scanning all mappings otherwise
+func (c *Client) FetchAuthorIds(address string, blockHash types.Hash) ([]AuthorMapping, error) {
+	if !c.HasStorage("AuthorMapping", "NimbusLookup") {
+		mappings, err := c.FetchAuthorMappings(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]AuthorMapping, 0)
+		for _, mapping := range mappings {
+			if strings.EqualFold(mapping.Account, address) {
+				result = append(result, mapping)
+			}
+		}
+		return result, nil
+	}
+	account, err := types.HexDecodeString(address)
+	if err != nil {
+		return nil, err
+	}
+	var nimbusId string
+	ok, err := c.GetOptionalStorageRawAt(
+		"AuthorMapping",
+		"NimbusLookup",
+		"NimbusId",
+		blockHash,
+		&nimbusId,
+		account,
+	)
+	if err != nil || !ok {
+		return []AuthorMapping{}, err
+	}
+	mapping, ok, err := c.FetchAuthorMapping(nimbusId, blockHash)
+	if err != nil || !ok {
+		return []AuthorMapping{}, err
+	}
+	return []AuthorMapping{mapping}, nil
+}
+
+// FetchAuthorMapping returns the account registered for a given nimbus id
+func (c *Client) FetchAuthorMapping(nimbusId string, blockHash types.Hash) (AuthorMapping, bool, error) {
+	key, err := types.HexDecodeString(nimbusId)
+	if err != nil {
+		return AuthorMapping{}, false, err
+	}
+	var registration registrationInfoUnmarshal
+	ok, err := c.GetOptionalStorageRawAt(
+		"AuthorMapping",
+		"MappingWithDeposit",
+		"RegistrationInfo",
+		blockHash,
+		&registration,
+		key,
+	)
+	if err != nil || !ok {
+		return AuthorMapping{}, false, err
+	}
+	return AuthorMapping{
+		NimbusId: strings.ToLower(nimbusId),
+		Account:  registration.Account,
+		Deposit:  registration.Deposit.AsBalance(&c.TokenInfo),
+	}, true, nil
+}
+
+// FetchAuthorMappings returns all the registered author mappings at given block
+func (c *Client) FetchAuthorMappings(blockHash types.Hash) ([]AuthorMapping, error) {
+	c.authorLock.Lock()
+	defer c.authorLock.Unlock()
+	cacheKey := fmt.Sprintf("AuthorMapping.MappingWithDeposit@%v", blockHash.Hex())
+	if cache, ok := c.getCache(cacheKey); ok {
+		if result, ok := cache.([]AuthorMapping); ok {
+			return result, nil
+		}
+	}
+	keys, err := c.GetStorageKeysAt("AuthorMapping", "MappingWithDeposit", blockHash)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Fetching %v author mappings", len(keys))
+	result := make([]AuthorMapping, 0, len(keys))
+	for _, key := range keys {
+		// Keys are blake2_128_concat so the nimbus id is the trailing 32 bytes
+		if len(key) < 32 {
+			continue
+		}
+		var registration registrationInfoUnmarshal
+		err := c.GetStorageRawByKeyAt(key, "RegistrationInfo", blockHash, &registration)
+		if err != nil {
+			return nil, err
+		}
+		if registration.Deposit.int == nil {
+			continue
+		}
+		result = append(result, AuthorMapping{
+			NimbusId: types.HexEncodeToString(key[len(key)-32:]),
+			Account:  registration.Account,
+			Deposit:  registration.Deposit.AsBalance(&c.TokenInfo),
+		})
+	}
+	c.setCache(cacheKey, result, config.DefaultCacheTTL())
+	return result, nil
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/async"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nimbusEngineId is the consensus engine id used by nimbus digests
+var nimbusEngineId = types.ConsensusEngineID(binary.LittleEndian.Uint32([]byte("nmbs")))
+
+type BlockAuthor struct {
+	Number   uint64     `json:"number"`
+	Hash     types.Hash `json:"hash"`
+	NimbusId string     `json:"nimbus_id,omitempty"`
+	Author   string     `json:"author"`
+	Collator string     `json:"collator"`
+	TsMillis uint64     `json:"ts"`
+	GapSecs  float64    `json:"gap"`
+}
+
+type CollatorBlocks struct {
+	Address      string  `json:"address"`
+	Display      string  `json:"display,omitempty"`
+	Collator     string  `json:"collator"`
+	Blocks       uint32  `json:"blocks"`
+	First        uint64  `json:"first"`
+	Last         uint64  `json:"last"`
+	MaxGapBlocks uint64  `json:"max_gap_blocks"`
+	MaxGapSecs   float64 `json:"max_gap"`
+	AvgGapSecs   float64 `json:"avg_gap"`
+}
+
+type RoundBlocks struct {
+	Round     uint32           `json:"round"`
+	First     uint64           `json:"first"`
+	Last      uint64           `json:"last"`
+	Blocks    []BlockAuthor    `json:"blocks"`
+	Collators []CollatorBlocks `json:"collators"`
+}
+
+func (cb *CollatorBlocks) DisplayName() string {
+	if cb.Address == "" {
+		return "unknown"
+	}
+	name := tools.ShortAddress(cb.Address)
+	if display := cb.Display; display != "" {
+		name = display
+	}
+	return name
+}
+
+// FetchRoundBlocks walks all the blocks of a round (up to the snap block) extracting the author from
+// the nimbus pre-runtime digest
+func (c *Client) FetchRoundBlocks(cfg config.BlocksScanConfig) (RoundBlocks, error) {
+	start := time.Now().UnixMilli()
+	round := cfg.Round
+	if round == 0 {
+		round = c.SnapRound.Number
+	}
+	if round > c.SnapRound.Number {
+		return RoundBlocks{}, fmt.Errorf("invalid round %v > %v", round, c.SnapRound.Number)
+	}
+	first := c.GetRoundStartBlock(round)
+	last := first + uint64(c.SnapRound.Length) - 1
+	if last > c.SnapBlock.Number {
+		last = c.SnapBlock.Number
+	}
+	// Author mappings at round start
+	roundHash, err := c.GetRoundStartHash(round)
+	if err != nil {
+		return RoundBlocks{}, err
+	}
+	mappings, err := c.FetchAuthorMappings(roundHash)
+	if err != nil {
+		return RoundBlocks{}, err
+	}
+	authors := make(map[string]string)
+	for _, mapping := range mappings {
+		authors[strings.ToLower(mapping.NimbusId)] = mapping.Account
+	}
+	// Orbiters author blocks on behalf of their collator pool
+	orbiters, err := c.FetchRoundOrbiters(round, roundHash)
+	if err != nil {
+		return RoundBlocks{}, err
+	}
+	// Walk blocks
+	log.Printf("Fetching %v blocks of round %v", last-first+1, round)
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	numbers := make(chan uint64)
+	ch := make(chan async.Result[BlockAuthor])
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				ch <- async.ResultFrom(c.fetchBlockAuthor(number, authors, orbiters))
+			}
+		}()
+	}
+	go func() {
+		for number := first; number <= last; number++ {
+			numbers <- number
+		}
+		close(numbers)
+		wg.Wait()
+		close(ch)
+	}()
+	// Collect, keep draining on error so workers can exit
+	blocks := make([]BlockAuthor, 0, last-first+1)
+	for r := range ch {
+		if r.Err != nil {
+			log.Printf("Unable to fetch block %v\n", r.Err)
+			err = r.Err
+		} else {
+			blocks = append(blocks, r.Value)
+		}
+	}
+	if err != nil {
+		return RoundBlocks{}, err
+	}
+	sort.Slice(blocks[:], func(i, j int) bool {
+		return blocks[i].Number < blocks[j].Number
+	})
+	for i := 1; i < len(blocks); i++ {
+		blocks[i].GapSecs = float64(blocks[i].TsMillis-blocks[i-1].TsMillis) / 1000.0
+	}
+	collators := collatorBlocksFrom(blocks)
+	for i := range collators {
+		if collators[i].Address == "" {
+			continue
+		}
+		info, err := c.FetchAccountInfo(collators[i].Address)
+		if err == nil {
+			collators[i].Display = info.Identity.Display
+		}
+	}
+	result := RoundBlocks{
+		Round:     round,
+		First:     first,
+		Last:      last,
+		Blocks:    blocks,
+		Collators: collators,
+	}
+	log.Printf("Fetched round blocks in %vsecs\n", float64(time.Now().UnixMilli()-start)/1000.0)
+	return result, nil
+}
+
+func (c *Client) fetchBlockAuthor(
+	number uint64,
+	authors map[string]string,
+	orbiters map[string]string,
+) (BlockAuthor, error) {
+	hash, err := c.api.RPC.Chain.GetBlockHash(number)
+	if err != nil {
+		return BlockAuthor{}, err
+	}
+	header, err := c.api.RPC.Chain.GetHeader(hash)
+	if err != nil {
+		return BlockAuthor{}, err
+	}
+	ts, err := fetchBlockTs(c, hash)
+	if err != nil {
+		return BlockAuthor{}, err
+	}
+	result := BlockAuthor{
+		Number:   number,
+		Hash:     hash,
+		TsMillis: ts,
+	}
+	for _, item := range header.Digest {
+		switch {
+		case item.IsPreRuntime && item.AsPreRuntime.ConsensusEngineID == nimbusEngineId:
+			result.NimbusId = types.HexEncodeToString(item.AsPreRuntime.Bytes)
+			if result.Author == "" {
+				result.Author = authors[result.NimbusId]
+			}
+		case item.IsConsensus && item.AsConsensus.ConsensusEngineID == nimbusEngineId:
+			// Newer runtimes also publish the author account directly
+			if len(item.AsConsensus.Bytes) == 20 {
+				result.Author = types.HexEncodeToString(item.AsConsensus.Bytes)
+			}
+		}
+	}
+	result.Collator = result.Author
+	if pool, ok := orbiters[strings.ToLower(result.Author)]; ok {
+		result.Collator = pool
+	}
+	return result, nil
+}
+
+// collatorBlocksFrom computes per author gaps between authored blocks, orbiters are reported separately
+// from their collator pool
+func collatorBlocksFrom(blocks []BlockAuthor) []CollatorBlocks {
+	byAuthor := make(map[string]*CollatorBlocks)
+	lastTs := make(map[string]uint64)
+	for _, block := range blocks {
+		author := strings.ToLower(block.Author)
+		cb, ok := byAuthor[author]
+		if !ok {
+			cb = &CollatorBlocks{Address: block.Author, Collator: block.Collator, First: block.Number}
+			byAuthor[author] = cb
+		} else {
+			gapBlocks := block.Number - cb.Last
+			gapSecs := float64(block.TsMillis-lastTs[author]) / 1000.0
+			if gapBlocks > cb.MaxGapBlocks {
+				cb.MaxGapBlocks = gapBlocks
+			}
+			if gapSecs > cb.MaxGapSecs {
+				cb.MaxGapSecs = gapSecs
+			}
+			cb.AvgGapSecs += gapSecs
+		}
+		cb.Blocks++
+		cb.Last = block.Number
+		lastTs[author] = block.TsMillis
+	}
+	result := make([]CollatorBlocks, 0, len(byAuthor))
+	for _, cb := range byAuthor {
+		if cb.Blocks > 1 {
+			cb.AvgGapSecs = cb.AvgGapSecs / float64(cb.Blocks-1)
+		}
+		result = append(result, *cb)
+	}
+	sort.Slice(result[:], func(i, j int) bool {
+		return result[i].Blocks > result[j].Blocks
+	})
+	return result
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestCollatorBlocksFrom(t *testing.T) {
+	blocks := []BlockAuthor{
+		{Number: 100, Author: "0xaa", TsMillis: 0},
+		{Number: 101, Author: "0xbb", TsMillis: 12000},
+		{Number: 102, Author: "0xAA", TsMillis: 24000},
+		{Number: 106, Author: "0xaa", TsMillis: 84000},
+	}
+	collators := collatorBlocksFrom(blocks)
+	if len(collators) != 2 {
+		t.Fatalf("got %v collators, wanted 2", len(collators))
+	}
+	cb := collators[0]
+	if cb.Blocks != 3 || cb.First != 100 || cb.Last != 106 {
+		t.Errorf("unexpected collator blocks %v", cb)
+	}
+	if cb.MaxGapBlocks != 4 || cb.MaxGapSecs != 60 || cb.AvgGapSecs != 42 {
+		t.Errorf("unexpected gaps %v", cb)
+	}
+}
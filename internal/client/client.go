@@ -7,20 +7,26 @@ import (
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/client"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/xxhash"
 	scalecodec "github.com/itering/scale.go"
 	"github.com/itering/scale.go/source"
 	types2 "github.com/itering/scale.go/types"
 	"github.com/itering/scale.go/utiles"
 	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/cache"
 	"log"
+	"sync"
 	"time"
 )
 
 type Client struct {
 	api         *gsrpc.SubstrateAPI
+	authorLock  sync.Mutex
 	cache       *mcache.CacheDriver
+	config      config.ChainConfig
 	metadata    *types.Metadata
 	decoder     scalecodec.MetadataDecoder
+	history     *cache.Disk
 	RpcUrl      string      `json:"endpoint"`
 	Chain       string      `json:"chain"`
 	SpecVersion int         `json:"spec"`
@@ -31,15 +37,17 @@ type Client struct {
 }
 
 func NewClient(config config.ChainConfig) (*Client, error) {
-	return NewClientWithExternalCache(config, mcache.New())
+	return NewClientWithExternalCache(config, mcache.New(), nil)
 }
 
-func NewClientWithExternalCache(cfg config.ChainConfig, cache *mcache.CacheDriver) (*Client, error) {
+// NewClientWithExternalCache creates a client sharing a cache, RPC calls are counted in stats when set
+func NewClientWithExternalCache(cfg config.ChainConfig, cache *mcache.CacheDriver, stats *RpcStats) (*Client, error) {
 	c := new(Client)
 	c.cache = cache
+	c.config = cfg
 	c.RpcUrl = cfg.RpcUrl()
 	// Create client first
-	api, err := gsrpc.NewSubstrateAPI(c.RpcUrl)
+	api, err := newSubstrateAPI(c.RpcUrl, stats)
 	if err != nil {
 		return c, err
 	}
@@ -56,7 +64,7 @@ func NewClientWithExternalCache(cfg config.ChainConfig, cache *mcache.CacheDrive
 		return c, err
 	}
 	// Get snap
-	snap, err := fetchSnapBlock(c, cfg.Snap.TargetBlock, cfg.Snap.TargetRound)
+	snap, err := fetchSnapBlock(c, cfg.Snap)
 	if err != nil {
 		return c, err
 	}
@@ -76,8 +84,8 @@ func NewClientWithExternalCache(cfg config.ChainConfig, cache *mcache.CacheDrive
 	}
 	c.SpecVersion = int(version.SpecVersion)
 	// Reload decoder
-	cfg.NetworkSpecsVersion = uint32(version.SpecVersion)
-	err = c.registerDecoder(cfg)
+	c.config.NetworkSpecsVersion = uint32(version.SpecVersion)
+	err = c.registerDecoder(c.config)
 	if err != nil {
 		return c, err
 	}
@@ -98,6 +106,42 @@ func NewClientWithExternalCache(cfg config.ChainConfig, cache *mcache.CacheDrive
 	return c, nil
 }
 
+// UpdateSnap moves the snap point to the current head reusing the connection, metadata is reloaded on
+// runtime upgrades
+func (c *Client) UpdateSnap() error {
+	return c.MoveSnap(config.SnapConfig{})
+}
+
+// MoveSnap moves the snap point to a given block, round or time reusing the connection
+func (c *Client) MoveSnap(target config.SnapConfig) error {
+	snap, err := fetchSnapBlock(c, target)
+	if err != nil {
+		return err
+	}
+	version, err := c.api.RPC.State.GetRuntimeVersion(snap.Block.Hash)
+	if err != nil {
+		return err
+	}
+	if int(version.SpecVersion) != c.SpecVersion {
+		log.Printf("Runtime upgraded from v%v to v%v, reloading metadata\n", c.SpecVersion, version.SpecVersion)
+		meta, err := c.api.RPC.State.GetMetadataLatest()
+		if err != nil {
+			return err
+		}
+		c.metadata = meta
+		c.config.NetworkSpecsVersion = uint32(version.SpecVersion)
+		err = c.registerDecoder(c.config)
+		if err != nil {
+			return err
+		}
+		c.SpecVersion = int(version.SpecVersion)
+	}
+	c.SnapBlock = snap.Block
+	c.SnapRound = snap.Round
+	c.SnapStaking = snap.Staking
+	return nil
+}
+
 func (c *Client) registerDecoder(cfg config.ChainConfig) error {
 	var hexMetadata string
 	err := client.CallWithBlockHash(c.api.Client, &hexMetadata, "state_getMetadata", nil)
@@ -124,11 +168,23 @@ func (c *Client) GetBlockNumber(hash types.Hash) (uint64, error) {
 	return uint64(headerLatest.Number), nil
 }
 
-func (c *Client) GetRoundStartHash(round uint32) (types.Hash, error) {
+// GetRoundStartBlock returns the first block of a round, assuming constant round length
+func (c *Client) GetRoundStartBlock(round uint32) uint64 {
 	roundDelta := c.SnapRound.Number - round
 	blockDelta := roundDelta * c.SnapRound.Length
-	startBlock := c.SnapRound.Start - blockDelta
-	return c.api.RPC.Chain.GetBlockHash(uint64(startBlock))
+	return uint64(c.SnapRound.Start - blockDelta)
+}
+
+func (c *Client) GetRoundStartHash(round uint32) (types.Hash, error) {
+	return c.api.RPC.Chain.GetBlockHash(c.GetRoundStartBlock(round))
+}
+
+// roundElapsedBlocks returns the number of blocks produced in a round up to the snap block
+func (c *Client) roundElapsedBlocks(round uint32) uint32 {
+	if round < c.SnapRound.Number {
+		return c.SnapRound.Length
+	}
+	return uint32(c.SnapBlock.Number) - c.SnapRound.Start + 1
 }
 
 func (c *Client) GetRoundEndHash(round uint32) (types.Hash, error) {
@@ -161,6 +217,54 @@ func (c *Client) GetStorageAt(
 	return c.api.RPC.State.GetStorage(key, target, blockHash)
 }
 
+// HasStorage checks if the runtime metadata exposes the given storage
+func (c *Client) HasStorage(pallet string, method string) bool {
+	_, err := c.metadata.FindStorageEntryMetadata(pallet, method)
+	return err == nil
+}
+
+// GetStorageKeysAt will fetch all the keys of a storage map at given block
+func (c *Client) GetStorageKeysAt(
+	pallet string,
+	method string,
+	blockHash types.Hash,
+) ([]types.StorageKey, error) {
+	prefix := append(xxhash.New128([]byte(pallet)).Sum(nil), xxhash.New128([]byte(method)).Sum(nil)...)
+	return c.api.RPC.State.GetKeys(prefix, blockHash)
+}
+
+// GetStorageRawByKeyAt will fetch storage for a full storage key at given block with default cache TTL
+func (c *Client) GetStorageRawByKeyAt(
+	key types.StorageKey,
+	typeString string,
+	blockHash types.Hash,
+	targetValue any,
+) error {
+	cacheKey := fmt.Sprintf("%v@%v", key.Hex(), blockHash.Hex())
+	cache, ok := c.getCache(cacheKey)
+	if ok {
+		err := json.Unmarshal(cache.([]byte), targetValue)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Cache err key %v", cacheKey)
+	}
+	raw, err := c.api.RPC.State.GetStorageRaw(key, blockHash)
+	if err != nil {
+		return err
+	}
+	j, err := c.decodeRawData(*raw, typeString)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(j, targetValue)
+	if err != nil {
+		return err
+	}
+	c.setCache(cacheKey, j, config.DefaultCacheTTL())
+	return nil
+}
+
 // GetStorageRaw will fetch storage at client snap block with minimum cache TTL (since we have no block reference)
 func (c *Client) GetStorageRaw(
 	pallet string,
@@ -199,6 +303,39 @@ func (c *Client) GetStorageRawAt(
 	return c.getStorage(pallet, method, typeString, blockHash, cacheTtl, cacheKey, targetValue, args...)
 }
 
+// GetOptionalStorageRawAt will fetch storage at given block with default cache TTL, returns false if
+// nothing is stored under the key
+func (c *Client) GetOptionalStorageRawAt(
+	pallet string,
+	method string,
+	typeString string,
+	blockHash types.Hash,
+	targetValue any,
+	args ...[]byte,
+) (bool, error) {
+	cacheKey := fmt.Sprintf("%v.%v(%v)?@%v", pallet, method, args, blockHash.Hex())
+	cache, ok := c.getCache(cacheKey)
+	if !ok {
+		r, err := c.getStorageData(pallet, method, blockHash, args...)
+		if err != nil {
+			return false, err
+		}
+		j := []byte("null")
+		if len(r) > 0 {
+			j, err = c.decodeRawData(r, typeString)
+			if err != nil {
+				return false, err
+			}
+		}
+		c.setCache(cacheKey, j, config.DefaultCacheTTL())
+		cache = j
+	}
+	if string(cache.([]byte)) == "null" {
+		return false, nil
+	}
+	return true, json.Unmarshal(cache.([]byte), targetValue)
+}
+
 // GetConstantValue will fetch a constant value and Marshal it as JSON
 func (c *Client) GetConstantValue(
 	pallet string,
@@ -6,6 +6,7 @@ import (
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/async"
+	"github.com/zooper-corp/mooncli/internal/tools"
 	"golang.org/x/exp/slices"
 	"log"
 	"math/big"
@@ -15,6 +16,9 @@ import (
 	"time"
 )
 
+// PointsPerBlock is the amount of points awarded to the block author
+const PointsPerBlock = 20
+
 type CandidatePoolEntry struct {
 	Owner  string
 	Amount TokenAmount
@@ -41,20 +45,32 @@ type CollatorInfo struct {
 	MinBond     TokenBalance               `json:"min_bond"`
 	Balance     AccountBalance             `json:"balance"`
 	Display     string                     `json:"display"`
+	Identity    AccountIdentity            `json:"identity"`
+	AuthorIds   []string                   `json:"author_ids,omitempty"`
+	Orbiters    []string                   `json:"orbiters,omitempty"`
 	History     map[uint32]CollatorHistory `json:"history,omitempty"`
 	Delegations []DelegatorState           `json:"-"`
 	Revokes     map[uint32]RevokeRound     `json:"revokes,omitempty"`
+	Reliability CollatorReliability        `json:"reliability"`
+	Apr         float64                    `json:"apr"`
 }
 
 type CollatorHistory struct {
-	Rank    uint32       `json:"rank"`
-	Blocks  uint32       `json:"blocks"`
-	Counted TokenBalance `json:"counted"`
+	Rank        uint32       `json:"rank"`
+	Blocks      uint32       `json:"blocks"`
+	Counted     TokenBalance `json:"counted"`
+	Selected    bool         `json:"selected"`
+	Expected    float32      `json:"expected"`
+	Orbiter     string       `json:"orbiter,omitempty"`
+	Delegations uint32       `json:"delegations"`
+	// Threshold is the counted stake of the last selected candidate
+	Threshold TokenBalance `json:"threshold"`
 }
 
 type CollatorPool struct {
 	SelectedSize uint32         `json:"selected_size"`
 	RoundNumber  uint32         `json:"round_number"`
+	Rewards      StakingRewards `json:"rewards"`
 	Collators    []CollatorInfo `json:"collators"`
 }
 
@@ -117,7 +133,7 @@ func (c *Client) GetCandidateBondLessDelay() (uint32, error) {
 }
 
 func (ci *CollatorInfo) DisplayName() string {
-	name := ci.Address[:6] + "..." + ci.Address[len(ci.Address)-4:]
+	name := tools.ShortAddress(ci.Address)
 	if display := ci.Display; display != "" {
 		name = display
 	}
@@ -139,11 +155,13 @@ func (ci *CollatorInfo) RevokeAt(round uint32) *RevokeRound {
 	return &lastV
 }
 
+// AverageBlocks is the average of blocks produced in history rounds where the collator was selected, rounds without
+// blocks are counted unless less than one block was expected (e.g. a round that just started)
 func (ci *CollatorInfo) AverageBlocks() float32 {
 	var r float32
 	var c int32
 	for _, history := range ci.History {
-		if history.Blocks > 0 {
+		if history.Blocks > 0 || (history.Selected && history.Expected >= 1) {
 			r = r + float32(history.Blocks)
 			c++
 		}
@@ -198,16 +216,20 @@ func (c *Client) FetchCollatorPool(poolConfig config.CollatorsPoolConfig) (Colla
 		close(ch)
 		log.Printf("Fetched collators in %vsecs\n", float64(time.Now().UnixMilli()-start)/1000.0)
 	}()
-	// Collect
+	// Collect, keep draining on error so that no fetch is left blocked
 	result := make([]CollatorInfo, 0)
+	var fetchErr error
 	for r := range ch {
 		if r.Err != nil {
 			log.Printf("Unable to fetch collator info %v\n", r.Err)
-			return CollatorPool{}, nil
+			fetchErr = r.Err
 		} else {
 			result = append(result, r.Value)
 		}
 	}
+	if fetchErr != nil {
+		return CollatorPool{}, fetchErr
+	}
 	// Sort
 	sort.Slice(result[:], func(i, j int) bool {
 		if result[i].Counted.Balance == nil {
@@ -229,6 +251,17 @@ func (c *Client) FetchCollatorPool(poolConfig config.CollatorsPoolConfig) (Colla
 		c.SnapRound.Number,
 		c.SnapRound.Number+c.SnapRound.RevokeDelay,
 	)
+	// Estimate returns, optional as reward parameters changed across runtimes
+	rewards, err := c.FetchStakingRewards(c.SnapBlock.Hash)
+	if err != nil {
+		log.Printf("Unable to fetch staking rewards %v\n", err)
+	} else {
+		collatorPool.Rewards = rewards
+		for i := range collatorPool.Collators {
+			info := &collatorPool.Collators[i]
+			info.Apr = rewards.DelegatorApr(float64(info.AverageBlocks()), c.SnapRound.Length, info.Counted.Float64())
+		}
+	}
 	// Done
 	return collatorPool, nil
 }
@@ -287,8 +320,22 @@ func (c *Client) FetchCollatorInfo(
 			return CollatorInfo{}, err
 		}
 	}
+	// Get nimbus author ids, optional as older runtimes might not support the lookup
+	authorIds := make([]string, 0)
+	mappings, err := c.FetchAuthorIds(address, c.SnapBlock.Hash)
+	if err != nil {
+		log.Printf("Unable to fetch author ids for %v: %v\n", address, err)
+	}
+	for _, mapping := range mappings {
+		authorIds = append(authorIds, mapping.NimbusId)
+	}
+	// Get orbiters if the collator is an orbiter pool
+	orbiters, err := c.FetchOrbiters(address, c.SnapBlock.Hash)
+	if err != nil {
+		log.Printf("Unable to fetch orbiters for %v: %v\n", address, err)
+	}
 	// Done
-	return CollatorInfo{
+	result := CollatorInfo{
 		Address:     address,
 		Selected:    selected,
 		Rank:        rank,
@@ -297,9 +344,14 @@ func (c *Client) FetchCollatorInfo(
 		MinBond:     candidate.TopAmount.AsBalance(&c.TokenInfo),
 		Balance:     info.Balance,
 		Display:     info.Identity.Display,
+		Identity:    info.Identity,
+		AuthorIds:   authorIds,
+		Orbiters:    orbiters,
 		History:     history,
 		Delegations: cd,
-	}, nil
+	}
+	result.Reliability = result.ComputeReliability()
+	return result, nil
 }
 
 func (c *Client) FetchCollatorBlocks(address string, round uint32, blockHash types.Hash) (uint32, error) {
@@ -324,16 +376,27 @@ func (c *Client) FetchCollatorBlocks(address string, round uint32, blockHash typ
 		log.Printf("Unable to get points")
 		return 0, err
 	}
-	return points / 20, nil
+	return points / PointsPerBlock, nil
 }
 
+// FetchCollatorHistory returns per round stats for the last history rounds, completed rounds are read from and
+// stored to the history cache when set
 func (c *Client) FetchCollatorHistory(
 	address string,
 	historyRounds uint32,
 ) (map[uint32]CollatorHistory, error) {
 	account, _ := types.HexDecodeString(address)
 	result := make(map[uint32]CollatorHistory, 0)
-	for i := c.SnapRound.Number; i >= c.SnapRound.Number-historyRounds; i-- {
+	cached := c.readHistoryCache(address)
+	firstRound := uint32(1)
+	if historyRounds < c.SnapRound.Number {
+		firstRound = c.SnapRound.Number - historyRounds
+	}
+	for i := c.SnapRound.Number; i >= firstRound; i-- {
+		if history, ok := cached[i]; ok && i < c.SnapRound.Number {
+			result[i] = history
+			continue
+		}
 		// Cache miss
 		blockHash := c.SnapBlock.Hash
 		if i < c.SnapRound.Number {
@@ -368,13 +431,53 @@ func (c *Client) FetchCollatorHistory(
 			return result, err
 		}
 		rank := getAddressRank(pool, address)
+		// Get selected set at round, expected blocks are an even share of the round
+		selected, err := c.FetchSelectedCandidates(blockHash)
+		if err != nil {
+			return result, err
+		}
+		// The pool is sorted ascending so the first selected candidate sets the selection threshold
+		threshold := TokenAmount{big.NewInt(0)}
+		for _, entry := range pool {
+			if containsAddress(selected, entry.Owner) {
+				threshold = entry.Amount
+				break
+			}
+		}
+		expected := float32(0)
+		if len(selected) > 0 {
+			expected = float32(c.roundElapsedBlocks(i)) / float32(len(selected))
+		}
+		// Orbiter pools rotate the author every round
+		orbiter, orbiterErr := c.FetchRoundOrbiter(address, i, blockHash)
+		if orbiterErr != nil {
+			log.Printf("Unable to fetch orbiter for %v at round %v: %v\n", address, i, orbiterErr)
+		}
 		// Ok
 		result[i] = CollatorHistory{
-			Blocks:  blocks,
-			Rank:    rank,
-			Counted: candidate.Counted.AsBalance(&c.TokenInfo),
+			Blocks:      blocks,
+			Rank:        rank,
+			Counted:     candidate.Counted.AsBalance(&c.TokenInfo),
+			Selected:    containsAddress(selected, address),
+			Expected:    expected,
+			Orbiter:     orbiter,
+			Delegations: candidate.Delegations,
+			Threshold:   threshold.AsBalance(&c.TokenInfo),
 		}
+		// Once a round is over its stats never change, retry rounds with a missing orbiter
+		if i < c.SnapRound.Number && orbiterErr == nil {
+			cached[i] = result[i]
+		}
+	}
+	// Keep the requested rounds and at least the cache retention window
+	oldest := uint32(0)
+	if c.SnapRound.Number > historyCacheRounds {
+		oldest = c.SnapRound.Number - historyCacheRounds
 	}
+	if firstRound < oldest {
+		oldest = firstRound
+	}
+	c.writeHistoryCache(address, cached, oldest)
 	return result, nil
 }
 
@@ -435,6 +538,15 @@ func (cp *CollatorPool) computeRevokes(firstRound uint32, lastRound uint32) {
 	}
 }
 
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+	return false
+}
+
 func getAddressRank(pool []CandidatePoolEntry, address string) uint32 {
 	for i := 0; i < len(pool); i++ {
 		if strings.EqualFold(pool[i].Owner, address) {
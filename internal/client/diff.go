@@ -0,0 +1,169 @@
+package client
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"sort"
+	"strings"
+)
+
+type PoolDiff struct {
+	From      DiffPoint      `json:"from"`
+	To        DiffPoint      `json:"to"`
+	Threshold float64        `json:"delegation_threshold"`
+	Collators []CollatorDiff `json:"collators"`
+}
+
+// DiffPoint is the snap point of one side of a diff
+type DiffPoint struct {
+	Round SnapRound `json:"round"`
+	Block SnapBlock `json:"block"`
+}
+
+type CollatorDiff struct {
+	Address string `json:"address"`
+	Display string `json:"display"`
+	// Pool is 'joined' or 'left' for candidates entering or leaving the pool
+	Pool         string  `json:"pool,omitempty"`
+	RankFrom     uint32  `json:"rank_from"`
+	RankTo       uint32  `json:"rank_to"`
+	CountedFrom  float64 `json:"counted_from"`
+	CountedTo    float64 `json:"counted_to"`
+	CountedDelta float64 `json:"counted_delta"`
+	// Selection is 'entered' or 'exited' when the selected status changed
+	Selection   string             `json:"selection,omitempty"`
+	Identity    *IdentityChange    `json:"identity,omitempty"`
+	Delegations []DelegationChange `json:"delegations,omitempty"`
+}
+
+type IdentityChange struct {
+	From AccountIdentity `json:"from"`
+	To   AccountIdentity `json:"to"`
+}
+
+type DelegationChange struct {
+	Delegator string `json:"delegator"`
+	// Change is 'new', 'removed' or 'changed'
+	Change string  `json:"change"`
+	From   float64 `json:"from"`
+	To     float64 `json:"to"`
+	Delta  float64 `json:"delta"`
+}
+
+// RankDelta returns the positions gained, negative when the collator lost positions
+func (cd *CollatorDiff) RankDelta() int64 {
+	if cd.RankFrom == 0 || cd.RankTo == 0 {
+		return 0
+	}
+	return int64(cd.RankFrom) - int64(cd.RankTo)
+}
+
+// Changed returns true if anything changed between the two snap points
+func (cd *CollatorDiff) Changed() bool {
+	return cd.Pool != "" ||
+		cd.RankFrom != cd.RankTo ||
+		cd.CountedDelta != 0 ||
+		cd.Selection != "" ||
+		cd.Identity != nil ||
+		len(cd.Delegations) > 0
+}
+
+// DiffPools compares two collator pools, only collators that changed are returned sorted by the final rank
+func DiffPools(from CollatorPool, to CollatorPool, diffConfig config.DiffConfig) PoolDiff {
+	result := PoolDiff{
+		Threshold: diffConfig.DelegationThreshold,
+		Collators: make([]CollatorDiff, 0),
+	}
+	addresses := make([]string, 0)
+	for _, pool := range []CollatorPool{to, from} {
+		for _, info := range pool.Collators {
+			if !containsAddress(addresses, info.Address) {
+				addresses = append(addresses, info.Address)
+			}
+		}
+	}
+	for _, address := range addresses {
+		before, hasBefore := from.CollatorInfoByAddress(address)
+		after, hasAfter := to.CollatorInfoByAddress(address)
+		cd := CollatorDiff{Address: address}
+		switch {
+		case !hasBefore:
+			cd.Pool = "joined"
+			cd.Display = after.DisplayName()
+		case !hasAfter:
+			cd.Pool = "left"
+			cd.Display = before.DisplayName()
+		default:
+			cd.Display = after.DisplayName()
+			if identityChanged(&before.Identity, &after.Identity) {
+				cd.Identity = &IdentityChange{From: before.Identity, To: after.Identity}
+			}
+		}
+		cd.RankFrom, cd.RankTo = before.Rank, after.Rank
+		cd.CountedFrom, cd.CountedTo = before.Counted.Float64(), after.Counted.Float64()
+		cd.CountedDelta = cd.CountedTo - cd.CountedFrom
+		if !before.Selected && after.Selected {
+			cd.Selection = "entered"
+		} else if before.Selected && !after.Selected {
+			cd.Selection = "exited"
+		}
+		cd.Delegations = diffDelegations(before.Delegations, after.Delegations, diffConfig.DelegationThreshold)
+		if cd.Changed() {
+			result.Collators = append(result.Collators, cd)
+		}
+	}
+	// Left candidates have no final rank and go last
+	sort.SliceStable(result.Collators, func(i, j int) bool {
+		a, b := result.Collators[i].RankTo, result.Collators[j].RankTo
+		if a == 0 || b == 0 {
+			return a != 0
+		}
+		return a < b
+	})
+	return result
+}
+
+func identityChanged(before *AccountIdentity, after *AccountIdentity) bool {
+	return before.Display != after.Display ||
+		before.Parent != after.Parent ||
+		before.Sub != after.Sub ||
+		before.Verified != after.Verified
+}
+
+// diffDelegations returns delegations added, removed or changed by at least the threshold, largest first
+func diffDelegations(before []DelegatorState, after []DelegatorState, threshold float64) []DelegationChange {
+	amounts := make(map[string]float64)
+	for _, d := range before {
+		amounts[strings.ToLower(d.Address)] = d.Amount.Float64()
+	}
+	result := make([]DelegationChange, 0)
+	for _, d := range after {
+		key := strings.ToLower(d.Address)
+		dc := DelegationChange{Delegator: d.Address, Change: "changed", To: d.Amount.Float64()}
+		if previous, ok := amounts[key]; ok {
+			dc.From = previous
+			delete(amounts, key)
+		} else {
+			dc.Change = "new"
+		}
+		dc.Delta = dc.To - dc.From
+		if dc.Delta != 0 && abs(dc.Delta) >= threshold {
+			result = append(result, dc)
+		}
+	}
+	for _, d := range before {
+		if amount, ok := amounts[strings.ToLower(d.Address)]; ok && amount >= threshold {
+			result = append(result, DelegationChange{Delegator: d.Address, Change: "removed", From: amount, Delta: -amount})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return abs(result[i].Delta) > abs(result[j].Delta)
+	})
+	return result
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"math/big"
+	"testing"
+)
+
+func testBalance(amount int64) TokenBalance {
+	info := TokenInfo{TokenDecimals: 0}
+	return TokenBalance{info: &info, Balance: &TokenAmount{big.NewInt(amount)}}
+}
+
+func testDelegation(address string, amount int64) DelegatorState {
+	return DelegatorState{Address: address, Amount: testBalance(amount)}
+}
+
+func TestDiffPools(t *testing.T) {
+	from := CollatorPool{
+		Collators: []CollatorInfo{
+			{Address: "0x01", Rank: 1, Selected: true, Counted: testBalance(500), Identity: AccountIdentity{Display: "a"},
+				Delegations: []DelegatorState{testDelegation("0xd1", 5000), testDelegation("0xd2", 200)}},
+			{Address: "0x02", Rank: 2, Selected: true, Counted: testBalance(400)},
+			{Address: "0x03", Rank: 3, Counted: testBalance(100)},
+			{Address: "0x04", Rank: 4, Counted: testBalance(50)},
+		},
+	}
+	to := CollatorPool{
+		Collators: []CollatorInfo{
+			{Address: "0x01", Rank: 1, Selected: true, Counted: testBalance(500), Identity: AccountIdentity{Display: "a"},
+				Delegations: []DelegatorState{testDelegation("0xd2", 300), testDelegation("0xD3", 2000)}},
+			{Address: "0x03", Rank: 2, Selected: true, Counted: testBalance(450)},
+			{Address: "0x02", Rank: 3, Counted: testBalance(400), Identity: AccountIdentity{Display: "b", Verified: true}},
+			{Address: "0x05", Rank: 4, Counted: testBalance(10)},
+		},
+	}
+	diff := DiffPools(from, to, config.DiffConfig{DelegationThreshold: 1000})
+	addresses := make([]string, 0)
+	for _, cd := range diff.Collators {
+		addresses = append(addresses, cd.Address)
+	}
+	if len(addresses) != 5 || addresses[0] != "0x01" || addresses[4] != "0x04" {
+		t.Fatalf("unexpected collators %v", addresses)
+	}
+	// Delegation below the threshold is ignored, removal and new delegation are reported
+	delegations := diff.Collators[0].Delegations
+	if len(delegations) != 2 || delegations[0].Change != "removed" || delegations[0].Delta != -5000 ||
+		delegations[1].Change != "new" || delegations[1].To != 2000 {
+		t.Errorf("unexpected delegations %+v", delegations)
+	}
+	entered := diff.Collators[1]
+	if entered.Selection != "entered" || entered.RankDelta() != 1 || entered.CountedDelta != 350 {
+		t.Errorf("unexpected diff %+v", entered)
+	}
+	exited := diff.Collators[2]
+	if exited.Selection != "exited" || exited.Identity == nil || exited.Identity.To.Display != "b" {
+		t.Errorf("unexpected diff %+v", exited)
+	}
+	if diff.Collators[3].Pool != "joined" || diff.Collators[4].Pool != "left" || diff.Collators[4].RankDelta() != 0 {
+		t.Errorf("unexpected pool changes %+v %+v", diff.Collators[3], diff.Collators[4])
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/cache"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// historyCacheVersion is bumped when CollatorHistory changes so that stale entries are fetched again
+const historyCacheVersion = 1
+
+// historyCacheRounds is the minimum number of rounds kept in the history cache of a collator, older rounds are
+// dropped unless a longer history was requested
+const historyCacheRounds = 336
+
+type historyCacheEntry struct {
+	Version int                        `json:"version"`
+	Rounds  map[uint32]CollatorHistory `json:"rounds"`
+}
+
+var chainKeyPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// SetHistoryCache enables the persistent cache of completed history rounds
+func (c *Client) SetHistoryCache(d *cache.Disk) {
+	c.history = d
+}
+
+func (c *Client) historyCacheKey(address string) string {
+	chain := strings.Trim(chainKeyPattern.ReplaceAllString(strings.ToLower(c.Chain), "-"), "-")
+	return fmt.Sprintf("%v/history/%v", chain, strings.ToLower(address))
+}
+
+// readHistoryCache returns cached rounds of a collator, never nil
+func (c *Client) readHistoryCache(address string) map[uint32]CollatorHistory {
+	result := make(map[uint32]CollatorHistory)
+	if c.history == nil {
+		return result
+	}
+	var entry historyCacheEntry
+	ok, err := c.history.Get(c.historyCacheKey(address), &entry)
+	if err != nil {
+		log.Printf("Unable to read history cache for %v: %v\n", address, err)
+	}
+	if !ok || err != nil || entry.Version != historyCacheVersion {
+		return result
+	}
+	// Balances are decoded with the chain token
+	SetTokenInfo(&entry, c.TokenInfo)
+	for round, history := range entry.Rounds {
+		result[round] = history
+	}
+	return result
+}
+
+// writeHistoryCache stores the rounds of a collator, rounds before the oldest one are dropped
+func (c *Client) writeHistoryCache(address string, rounds map[uint32]CollatorHistory, oldest uint32) {
+	if c.history == nil || len(rounds) == 0 {
+		return
+	}
+	for round := range rounds {
+		if round < oldest {
+			delete(rounds, round)
+		}
+	}
+	err := c.history.Set(c.historyCacheKey(address), historyCacheEntry{Version: historyCacheVersion, Rounds: rounds})
+	if err != nil {
+		log.Printf("Unable to write history cache for %v: %v\n", address, err)
+	}
+}
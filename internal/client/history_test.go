@@ -0,0 +1,38 @@
+package client
+
+import (
+	"github.com/zooper-corp/mooncli/internal/cache"
+	"math"
+	"testing"
+)
+
+func TestHistoryCache(t *testing.T) {
+	d, err := cache.NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{Chain: "Moonbase Alpha", TokenInfo: TokenInfo{TokenDecimals: 18}}
+	if key := c.historyCacheKey("0xABC"); key != "moonbase-alpha/history/0xabc" {
+		t.Errorf("unexpected key %v", key)
+	}
+	if rounds := c.readHistoryCache("0xabc"); len(rounds) != 0 {
+		t.Errorf("expected empty history without cache")
+	}
+	c.SetHistoryCache(d)
+	threshold := TokenAmount{}
+	_ = threshold.UnmarshalJSON([]byte("2500000000000000000000"))
+	c.writeHistoryCache("0xabc", map[uint32]CollatorHistory{
+		8:  {Rank: 5},
+		10: {Rank: 3, Blocks: 12, Selected: true, Delegations: 300, Threshold: threshold.AsBalance(&c.TokenInfo)},
+	}, 9)
+	rounds := c.readHistoryCache("0xABC")
+	if h, ok := rounds[10]; !ok || h.Rank != 3 || h.Delegations != 300 || math.Abs(h.Threshold.Float64()-2500) > 1e-6 {
+		t.Errorf("unexpected cached history %+v", rounds)
+	}
+	if h := rounds[10].Threshold; h.Balance == nil || h.Balance.AsBigInt().String() != "2500000000000000000000" {
+		t.Errorf("unexpected cached threshold %+v", h.Balance)
+	}
+	if _, ok := rounds[8]; ok {
+		t.Errorf("expected round 8 to be dropped from the cache")
+	}
+}
@@ -1,22 +1,95 @@
 package client
 
 import (
+	"fmt"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"strings"
 	"time"
 )
 
+type identityDataUnmarshal struct {
+	Raw string
+}
+
 type registrationUnmarshal struct {
+	Judgements []struct {
+		Col1 uint32
+		Col2 map[string]interface{}
+	}
 	Info struct {
-		Display struct {
-			Raw string
-		}
+		Display identityDataUnmarshal
+		Legal   identityDataUnmarshal
+		Web     identityDataUnmarshal
+		Riot    identityDataUnmarshal
+		Email   identityDataUnmarshal
+		Twitter identityDataUnmarshal
 	}
 }
 
+type superOfUnmarshal struct {
+	Col1 string
+	Col2 identityDataUnmarshal
+}
+
 type AccountIdentity struct {
-	Display string `json:"display,omitempty"`
+	Display    string              `json:"display,omitempty"`
+	Legal      string              `json:"legal,omitempty"`
+	Web        string              `json:"web,omitempty"`
+	Email      string              `json:"email,omitempty"`
+	Twitter    string              `json:"twitter,omitempty"`
+	Riot       string              `json:"riot,omitempty"`
+	Parent     string              `json:"parent,omitempty"`
+	Sub        string              `json:"sub,omitempty"`
+	Judgements []IdentityJudgement `json:"judgements,omitempty"`
+	Verified   bool                `json:"verified"`
+}
+
+type IdentityJudgement struct {
+	Registrar uint32 `json:"registrar"`
+	Judgement string `json:"judgement"`
 }
 
 func (c *Client) accountIdentityFromAccount(account []byte) (AccountIdentity, error) {
+	identity, err := c.fetchRegistration(account)
+	if err != nil {
+		return AccountIdentity{}, err
+	}
+	if identity.Display != "" || len(identity.Judgements) > 0 {
+		return identity, nil
+	}
+	// No identity, check if this is a sub identity
+	var super superOfUnmarshal
+	err = c.GetStorageRawWithTtl(
+		"Identity",
+		"SuperOf",
+		"(AccountId, Data)",
+		6*time.Hour,
+		&super,
+		account,
+	)
+	if err != nil || len(super.Col1) != 42 {
+		return identity, err
+	}
+	parentAccount, err := types.HexDecodeString(super.Col1)
+	if err != nil {
+		return identity, err
+	}
+	parent, err := c.fetchRegistration(parentAccount)
+	if err != nil {
+		return identity, err
+	}
+	// Sub identities inherit parent info and judgements
+	parent.Parent = super.Col1
+	parent.Sub = super.Col2.Raw
+	if parent.Display != "" && parent.Sub != "" {
+		parent.Display = fmt.Sprintf("%v/%v", parent.Display, parent.Sub)
+	} else if parent.Sub != "" {
+		parent.Display = parent.Sub
+	}
+	return parent, nil
+}
+
+func (c *Client) fetchRegistration(account []byte) (AccountIdentity, error) {
 	var result registrationUnmarshal
 	err := c.GetStorageRawWithTtl(
 		"Identity",
@@ -29,9 +102,23 @@ func (c *Client) accountIdentityFromAccount(account []byte) (AccountIdentity, er
 	if err != nil {
 		return AccountIdentity{}, err
 	}
-	// Done
 	r := AccountIdentity{
-		Display: result.Info.Display.Raw,
+		Display:    result.Info.Display.Raw,
+		Legal:      result.Info.Legal.Raw,
+		Web:        result.Info.Web.Raw,
+		Email:      result.Info.Email.Raw,
+		Twitter:    result.Info.Twitter.Raw,
+		Riot:       result.Info.Riot.Raw,
+		Judgements: make([]IdentityJudgement, 0),
+	}
+	for _, judgement := range result.Judgements {
+		for k := range judgement.Col2 {
+			r.Judgements = append(r.Judgements, IdentityJudgement{Registrar: judgement.Col1, Judgement: k})
+			if strings.EqualFold(k, "Reasonable") || strings.EqualFold(k, "KnownGood") {
+				r.Verified = true
+			}
+			break
+		}
 	}
 	return r, nil
 }
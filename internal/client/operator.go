@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/config"
+	"strings"
+)
+
+type OperatorReport struct {
+	Address     string          `json:"address"`
+	Display     string          `json:"display,omitempty"`
+	Candidate   bool            `json:"candidate"`
+	Selected    bool            `json:"selected"`
+	Rank        uint32          `json:"rank,omitempty"`
+	AuthorIds   []AuthorMapping `json:"author_ids"`
+	Round       uint32          `json:"round"`
+	RoundBlocks uint32          `json:"round_blocks"`
+	Blocks      uint32          `json:"blocks"`
+	Issues      []string        `json:"issues"`
+}
+
+func (or *OperatorReport) Healthy() bool {
+	return len(or.Issues) == 0
+}
+
+// CheckOperator looks for the most common collator setup failures: a missing nimbus key mapping
+// or a selected collator that is not producing blocks in the current round
+func (c *Client) CheckOperator(cfg config.OperatorCheckConfig) (OperatorReport, error) {
+	report := OperatorReport{
+		Address:     cfg.Address,
+		AuthorIds:   []AuthorMapping{},
+		Round:       c.SnapRound.Number,
+		RoundBlocks: c.roundElapsedBlocks(c.SnapRound.Number),
+		Issues:      []string{},
+	}
+	pool, err := c.FetchSortedCandidatePool(c.SnapBlock.Hash)
+	if err != nil {
+		return report, err
+	}
+	for _, poolEntry := range pool {
+		if strings.EqualFold(poolEntry.Owner, cfg.Address) {
+			report.Candidate = true
+			report.Rank = getAddressRank(pool, cfg.Address)
+			break
+		}
+	}
+	selected, err := c.FetchSelectedCandidates(c.SnapBlock.Hash)
+	if err != nil {
+		return report, err
+	}
+	report.Selected = containsAddress(selected, cfg.Address)
+	// Identity is optional
+	info, err := c.FetchAccountInfo(cfg.Address)
+	if err == nil {
+		report.Display = info.Identity.Display
+	}
+	// Author mapping
+	mappings, err := c.FetchAuthorIds(cfg.Address, c.SnapBlock.Hash)
+	if err != nil {
+		return report, err
+	}
+	report.AuthorIds = mappings
+	// Blocks in the current round
+	report.Blocks, err = c.FetchCollatorBlocks(cfg.Address, c.SnapRound.Number, c.SnapBlock.Hash)
+	if err != nil {
+		return report, err
+	}
+	// Checks
+	if !report.Candidate {
+		report.Issues = append(report.Issues, "not in the candidate pool")
+	}
+	if report.Selected && len(report.AuthorIds) == 0 {
+		report.Issues = append(report.Issues, "selected but no nimbus author id is mapped")
+	}
+	if report.Selected && report.Blocks == 0 && report.RoundBlocks >= cfg.MinRoundBlocks {
+		report.Issues = append(
+			report.Issues,
+			fmt.Sprintf("selected but no blocks produced after %v blocks in round %v", report.RoundBlocks, report.Round),
+		)
+	}
+	return report, nil
+}
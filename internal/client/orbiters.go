@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"strings"
+)
+
+type currentOrbiterUnmarshal struct {
+	AccountId string
+	Removed   bool
+}
+
+type collatorPoolInfoUnmarshal struct {
+	Orbiters            []currentOrbiterUnmarshal
+	MaybeCurrentOrbiter *currentOrbiterUnmarshal
+	NextOrbiter         uint32
+}
+
+// HasOrbiters checks if the runtime supports orbiter collator pools
+func (c *Client) HasOrbiters() bool {
+	return c.HasStorage("MoonbeamOrbiters", "CollatorsPool")
+}
+
+// FetchOrbiters returns the orbiters sharing the slot of a collator pool, empty if the collator is not a pool
+func (c *Client) FetchOrbiters(address string, blockHash types.Hash) ([]string, error) {
+	result := make([]string, 0)
+	if !c.HasOrbiters() {
+		return result, nil
+	}
+	account, err := types.HexDecodeString(address)
+	if err != nil {
+		return result, err
+	}
+	var pool collatorPoolInfoUnmarshal
+	ok, err := c.GetOptionalStorageRawAt(
+		"MoonbeamOrbiters",
+		"CollatorsPool",
+		"CollatorPoolInfo",
+		blockHash,
+		&pool,
+		account,
+	)
+	if err != nil || !ok {
+		return result, err
+	}
+	for _, orbiter := range pool.Orbiters {
+		if !orbiter.Removed {
+			result = append(result, orbiter.AccountId)
+		}
+	}
+	return result, nil
+}
+
+// FetchRoundOrbiter returns the orbiter active for a collator pool in a given round, empty if none
+func (c *Client) FetchRoundOrbiter(address string, round uint32, blockHash types.Hash) (string, error) {
+	if !c.HasOrbiters() {
+		return "", nil
+	}
+	account, err := types.HexDecodeString(address)
+	if err != nil {
+		return "", err
+	}
+	var roundEncoded = bytes.Buffer{}
+	err = scale.NewEncoder(&roundEncoded).Encode(types.NewU32(round))
+	if err != nil {
+		return "", err
+	}
+	var orbiter string
+	_, err = c.GetOptionalStorageRawAt(
+		"MoonbeamOrbiters",
+		"OrbiterPerRound",
+		"AccountId",
+		blockHash,
+		&orbiter,
+		roundEncoded.Bytes(),
+		account,
+	)
+	return orbiter, err
+}
+
+// FetchRoundOrbiters returns a map of active orbiter to collator pool for a given round
+func (c *Client) FetchRoundOrbiters(round uint32, blockHash types.Hash) (map[string]string, error) {
+	result := make(map[string]string)
+	if !c.HasOrbiters() {
+		return result, nil
+	}
+	keys, err := c.GetStorageKeysAt("MoonbeamOrbiters", "CollatorsPool", blockHash)
+	if err != nil {
+		return result, err
+	}
+	for _, key := range keys {
+		// Keys are blake2_128_concat so the pool account is the trailing 20 bytes
+		if len(key) < 20 {
+			continue
+		}
+		pool := types.HexEncodeToString(key[len(key)-20:])
+		orbiter, err := c.FetchRoundOrbiter(pool, round, blockHash)
+		if err != nil {
+			return result, err
+		}
+		if orbiter != "" {
+			result[strings.ToLower(orbiter)] = pool
+		}
+	}
+	return result, nil
+}
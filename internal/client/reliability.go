@@ -0,0 +1,106 @@
+package client
+
+import (
+	"golang.org/x/exp/slices"
+)
+
+type CollatorReliability struct {
+	SelectedRounds uint32               `json:"selected_rounds"`
+	IdleRounds     uint32               `json:"idle_rounds"`
+	Expected       float32              `json:"expected"`
+	Blocks         uint32               `json:"blocks"`
+	Ratio          float32              `json:"ratio"`
+	ZeroRounds     uint32               `json:"zero_rounds"`
+	LongestStreak  uint32               `json:"longest_zero_streak"`
+	CurrentStreak  uint32               `json:"current_zero_streak"`
+	Streaks        []ZeroStreak         `json:"zero_streaks,omitempty"`
+	Orbiters       []OrbiterReliability `json:"orbiters,omitempty"`
+}
+
+type OrbiterReliability struct {
+	Address    string  `json:"address"`
+	Rounds     uint32  `json:"rounds"`
+	Expected   float32 `json:"expected"`
+	Blocks     uint32  `json:"blocks"`
+	Ratio      float32 `json:"ratio"`
+	ZeroRounds uint32  `json:"zero_rounds"`
+}
+
+type ZeroStreak struct {
+	First  uint32 `json:"first"`
+	Last   uint32 `json:"last"`
+	Rounds uint32 `json:"rounds"`
+}
+
+// ComputeReliability compares produced blocks against the expected share of each round in history,
+// rounds where the collator was not selected are only counted as idle
+func (ci *CollatorInfo) ComputeReliability() CollatorReliability {
+	rounds := make([]uint32, 0, len(ci.History))
+	for round := range ci.History {
+		rounds = append(rounds, round)
+	}
+	slices.Sort(rounds)
+	r := CollatorReliability{}
+	orbiters := make(map[string]*OrbiterReliability)
+	orbiterOrder := make([]string, 0)
+	var streak *ZeroStreak
+	for _, round := range rounds {
+		history := ci.History[round]
+		if !history.Selected {
+			r.IdleRounds++
+			streak = nil
+			continue
+		}
+		r.SelectedRounds++
+		r.Expected += history.Expected
+		r.Blocks += history.Blocks
+		// Orbiter pools, blocks in a round belong to the active orbiter
+		if history.Orbiter != "" {
+			o, ok := orbiters[history.Orbiter]
+			if !ok {
+				o = &OrbiterReliability{Address: history.Orbiter}
+				orbiters[history.Orbiter] = o
+				orbiterOrder = append(orbiterOrder, history.Orbiter)
+			}
+			o.Rounds++
+			o.Expected += history.Expected
+			o.Blocks += history.Blocks
+			if history.Blocks == 0 && history.Expected >= 1 {
+				o.ZeroRounds++
+			}
+		}
+		if history.Blocks > 0 {
+			streak = nil
+			continue
+		}
+		// A round in progress with less than one expected block is not an outage yet
+		if history.Expected < 1 {
+			continue
+		}
+		r.ZeroRounds++
+		if streak != nil && streak.Last+1 == round {
+			streak.Last = round
+			streak.Rounds++
+		} else {
+			r.Streaks = append(r.Streaks, ZeroStreak{First: round, Last: round, Rounds: 1})
+			streak = &r.Streaks[len(r.Streaks)-1]
+		}
+		if streak.Rounds > r.LongestStreak {
+			r.LongestStreak = streak.Rounds
+		}
+	}
+	if streak != nil {
+		r.CurrentStreak = streak.Rounds
+	}
+	if r.Expected > 0 {
+		r.Ratio = float32(r.Blocks) / r.Expected
+	}
+	for _, address := range orbiterOrder {
+		o := orbiters[address]
+		if o.Expected > 0 {
+			o.Ratio = float32(o.Blocks) / o.Expected
+		}
+		r.Orbiters = append(r.Orbiters, *o)
+	}
+	return r
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestCollatorInfo_ComputeReliability(t *testing.T) {
+	collator := CollatorInfo{
+		History: map[uint32]CollatorHistory{
+			10: {Blocks: 30, Selected: true, Expected: 28},
+			11: {Blocks: 0, Selected: true, Expected: 28},
+			12: {Blocks: 0, Selected: true, Expected: 28},
+			13: {Blocks: 0, Selected: false, Expected: 28},
+			14: {Blocks: 0, Selected: true, Expected: 28},
+			15: {Blocks: 0, Selected: true, Expected: 0.5},
+		},
+	}
+	r := collator.ComputeReliability()
+	if r.SelectedRounds != 5 || r.IdleRounds != 1 {
+		t.Errorf("got %v selected %v idle, wanted 5 and 1", r.SelectedRounds, r.IdleRounds)
+	}
+	if r.ZeroRounds != 3 {
+		t.Errorf("got %v zero rounds, wanted 3", r.ZeroRounds)
+	}
+	if r.LongestStreak != 2 || r.CurrentStreak != 1 {
+		t.Errorf("got longest %v current %v, wanted 2 and 1", r.LongestStreak, r.CurrentStreak)
+	}
+	if len(r.Streaks) != 2 || r.Streaks[0].First != 11 || r.Streaks[0].Last != 12 {
+		t.Errorf("unexpected streaks %v", r.Streaks)
+	}
+	if r.Blocks != 30 || r.Ratio < 0.26 || r.Ratio > 0.27 {
+		t.Errorf("got blocks %v ratio %v, wanted 30 and 0.266", r.Blocks, r.Ratio)
+	}
+}
+
+func TestCollatorInfo_ComputeReliability_Orbiters(t *testing.T) {
+	collator := CollatorInfo{
+		History: map[uint32]CollatorHistory{
+			10: {Blocks: 20, Selected: true, Expected: 20, Orbiter: "0xaa"},
+			11: {Blocks: 0, Selected: true, Expected: 20, Orbiter: "0xbb"},
+			12: {Blocks: 10, Selected: true, Expected: 20, Orbiter: "0xaa"},
+		},
+	}
+	r := collator.ComputeReliability()
+	if len(r.Orbiters) != 2 {
+		t.Fatalf("got %v orbiters, wanted 2", len(r.Orbiters))
+	}
+	if r.Orbiters[0].Address != "0xaa" || r.Orbiters[0].Blocks != 30 || r.Orbiters[0].Rounds != 2 {
+		t.Errorf("unexpected orbiter %v", r.Orbiters[0])
+	}
+	if r.Orbiters[1].ZeroRounds != 1 || r.Orbiters[1].Ratio != 0 {
+		t.Errorf("unexpected orbiter %v", r.Orbiters[1])
+	}
+}
+
+func TestCollatorInfo_AverageBlocks(t *testing.T) {
+	collator := CollatorInfo{
+		History: map[uint32]CollatorHistory{
+			10: {Blocks: 30, Selected: true, Expected: 28},
+			11: {Blocks: 0, Selected: true, Expected: 28},
+			12: {Blocks: 0, Selected: false, Expected: 28},
+			13: {Blocks: 0, Selected: true, Expected: 0.5},
+		},
+	}
+	// Outages lower the average, idle rounds and a round that just started do not
+	if avg := collator.AverageBlocks(); avg != 15 {
+		t.Errorf("got %v average blocks, wanted 15", avg)
+	}
+}
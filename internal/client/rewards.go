@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"math/big"
+)
+
+const perbill = 1_000_000_000
+
+type perbillRangeUnmarshal struct {
+	Min   types.U32
+	Ideal types.U32
+	Max   types.U32
+}
+
+type inflationInfoUnmarshal struct {
+	Expect struct {
+		Min   types.U128
+		Ideal types.U128
+		Max   types.U128
+	}
+	Annual perbillRangeUnmarshal
+	Round  perbillRangeUnmarshal
+}
+
+type parachainBondUnmarshal struct {
+	Account types.H160
+	Percent types.U8
+}
+
+// StakingRewards holds the parameters used to estimate staking returns
+type StakingRewards struct {
+	RoundIssuance TokenBalance `json:"round_issuance"`
+	BondReserve   float64      `json:"bond_reserve"`
+	Commission    float64      `json:"commission"`
+	RoundsPerYear float64      `json:"rounds_per_year"`
+}
+
+// FetchStakingRewards returns the ideal round issuance together with the parachain bond reserve and the
+// collator commission, both as fractions
+func (c *Client) FetchStakingRewards(blockHash types.Hash) (StakingRewards, error) {
+	var inflation inflationInfoUnmarshal
+	_, err := c.GetStorageAt("ParachainStaking", "InflationConfig", &inflation, blockHash)
+	if err != nil {
+		return StakingRewards{}, err
+	}
+	var issuance types.U128
+	_, err = c.GetStorageAt("Balances", "TotalIssuance", &issuance, blockHash)
+	if err != nil {
+		return StakingRewards{}, err
+	}
+	var commission types.U32
+	_, err = c.GetStorageAt("ParachainStaking", "CollatorCommission", &commission, blockHash)
+	if err != nil {
+		return StakingRewards{}, err
+	}
+	// Older and newer runtimes might not have the bond reserve, without it returns would be overstated
+	if !c.HasStorage("ParachainStaking", "ParachainBondInfo") {
+		return StakingRewards{}, fmt.Errorf("parachain bond info not available, unable to estimate returns")
+	}
+	var bond parachainBondUnmarshal
+	_, err = c.GetStorageAt("ParachainStaking", "ParachainBondInfo", &bond, blockHash)
+	if err != nil {
+		return StakingRewards{}, err
+	}
+	// Round issuance is a fraction of total supply
+	roundIssuance := big.NewInt(0).Mul(issuance.Int, big.NewInt(int64(inflation.Round.Ideal)))
+	roundIssuance.Div(roundIssuance, big.NewInt(perbill))
+	result := StakingRewards{
+		RoundIssuance: TokenBalance{info: &c.TokenInfo, Balance: &TokenAmount{roundIssuance}},
+		BondReserve:   float64(bond.Percent) / 100.0,
+		Commission:    float64(commission) / perbill,
+	}
+	roundSecs := float64(c.SnapRound.Length) * c.SnapBlock.DurationSecs
+	if roundSecs > 0 {
+		result.RoundsPerYear = 365 * 24 * 3600 / roundSecs
+	}
+	return result, nil
+}
+
+// DelegatorApr estimates the yearly return of delegators given the average blocks produced per round,
+// rewards are shared among collators by points and among delegators by stake
+func (sr *StakingRewards) DelegatorApr(averageBlocks float64, roundLength uint32, counted float64) float64 {
+	if counted <= 0 || roundLength == 0 {
+		return 0
+	}
+	staking := sr.RoundIssuance.Float64() * (1 - sr.BondReserve)
+	delegators := staking * (1 - sr.Commission) * averageBlocks / float64(roundLength)
+	return delegators * sr.RoundsPerYear / counted
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestStakingRewards_DelegatorApr(t *testing.T) {
+	// 1000 tokens per round, half of it to the bond reserve and a 20% commission
+	issuance, _ := big.NewInt(0).SetString("1000000000000000000000", 10)
+	rewards := StakingRewards{
+		RoundIssuance: TokenBalance{
+			info:    &TokenInfo{TokenDecimals: 18, TokenSymbol: "TEST"},
+			Balance: &TokenAmount{issuance},
+		},
+		BondReserve:   0.5,
+		Commission:    0.2,
+		RoundsPerYear: 1000,
+	}
+	// A collator producing 10% of the blocks gets 40 tokens per round for delegators
+	apr := rewards.DelegatorApr(180, 1800, 100000)
+	if math.Abs(apr-0.4) > 1e-9 {
+		t.Errorf("got %v, wanted 0.4", apr)
+	}
+	if rewards.DelegatorApr(180, 1800, 0) != 0 {
+		t.Errorf("expected no apr without stake")
+	}
+}
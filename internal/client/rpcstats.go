@@ -0,0 +1,81 @@
+package client
+
+import (
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/client"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/rpc"
+	"sync"
+)
+
+// RpcCallStats are the calls made to a RPC method
+type RpcCallStats struct {
+	Calls  uint64 `json:"calls"`
+	Errors uint64 `json:"errors"`
+}
+
+// RpcStats counts the RPC calls of the clients sharing it
+type RpcStats struct {
+	lock    sync.Mutex
+	methods map[string]RpcCallStats
+}
+
+func NewRpcStats() *RpcStats {
+	return &RpcStats{methods: make(map[string]RpcCallStats)}
+}
+
+// Calls returns a copy of the RPC call counters by method, empty for nil stats
+func (s *RpcStats) Calls() map[string]RpcCallStats {
+	result := make(map[string]RpcCallStats)
+	if s == nil {
+		return result
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for method, stats := range s.methods {
+		result[method] = stats
+	}
+	return result
+}
+
+func (s *RpcStats) record(method string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats := s.methods[method]
+	stats.Calls++
+	if err != nil {
+		stats.Errors++
+	}
+	s.methods[method] = stats
+}
+
+// countingClient counts calls made through the RPC client
+type countingClient struct {
+	client.Client
+	stats *RpcStats
+}
+
+func (cc countingClient) Call(result interface{}, method string, args ...interface{}) error {
+	err := cc.Client.Call(result, method, args...)
+	cc.stats.record(method, err)
+	return err
+}
+
+// newSubstrateAPI is gsrpc.NewSubstrateAPI with RPC calls counted in stats when set
+func newSubstrateAPI(url string, stats *RpcStats) (*gsrpc.SubstrateAPI, error) {
+	if stats == nil {
+		return gsrpc.NewSubstrateAPI(url)
+	}
+	cl, err := client.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	counting := countingClient{cl, stats}
+	newRPC, err := rpc.NewRPC(counting)
+	if err != nil {
+		return nil, err
+	}
+	return &gsrpc.SubstrateAPI{
+		RPC:    newRPC,
+		Client: counting,
+	}, nil
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"fmt"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/zooper-corp/mooncli/config"
 	"math"
+	"time"
 )
 
 type Snap struct {
@@ -44,7 +46,57 @@ func fetchBlockTs(c *Client, blockHash types.Hash) (uint64, error) {
 	return uint64(blockTs), nil
 }
 
-func fetchSnapBlock(c *Client, targetBlock int64, targetRound uint32) (Snap, error) {
+// FindBlockAt returns the last block produced at or before a given time
+func (c *Client) FindBlockAt(target time.Time) (uint64, error) {
+	headHash, err := c.api.RPC.Chain.GetBlockHashLatest()
+	if err != nil {
+		return 0, err
+	}
+	head, err := c.GetBlockNumber(headHash)
+	if err != nil {
+		return 0, err
+	}
+	blockTs := func(number uint64) (uint64, error) {
+		hash, err := c.api.RPC.Chain.GetBlockHash(number)
+		if err != nil {
+			return 0, err
+		}
+		return fetchBlockTs(c, hash)
+	}
+	targetTs := uint64(target.UnixMilli())
+	// Genesis has no timestamp, search from block 1
+	firstTs, err := blockTs(1)
+	if err != nil {
+		return 0, err
+	}
+	if targetTs < firstTs {
+		return 0, fmt.Errorf("time %v is before the first block", target)
+	}
+	low, high := uint64(1), head
+	for low < high {
+		mid := (low + high + 1) / 2
+		ts, err := blockTs(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ts <= targetTs {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
+
+func fetchSnapBlock(c *Client, target config.SnapConfig) (Snap, error) {
+	targetBlock, targetRound := target.TargetBlock, target.TargetRound
+	if !target.TargetTime.IsZero() {
+		block, err := c.FindBlockAt(target.TargetTime)
+		if err != nil {
+			return Snap{}, err
+		}
+		targetBlock, targetRound = int64(block), 0
+	}
 	// Get head block
 	blockHash, err := c.api.RPC.Chain.GetBlockHashLatest()
 	if err != nil {
@@ -72,11 +72,17 @@ func TokenBalanceU128(c *Client, u128 types.U128) TokenBalance {
 }
 
 type TokenBalance struct {
-	info    *TokenInfo
+	info *TokenInfo
+	// decoded is the token amount read from JSON, converted once the token info is set
+	decoded *big.Float
 	Balance *TokenAmount `json:"balance"`
 }
 
 func (tb *TokenBalance) Float64() float64 {
+	if tb.info == nil && tb.decoded != nil {
+		result, _ := tb.decoded.Float64()
+		return result
+	}
 	if tb.info != nil && tb.Balance != nil {
 		fb := new(big.Float).SetInt(tb.Balance.int)
 		fe := new(big.Float).SetFloat64(math.Pow10(int(tb.info.TokenDecimals) * -1))
@@ -96,8 +102,8 @@ func (tb TokenBalance) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// UnmarshalJSON reads a token amount, the balance is set once the token info is known, see SetTokenInfo
 func (tb *TokenBalance) UnmarshalJSON(p []byte) error {
-	ti := GlobalTokenInfo
 	if string(p) == "null" {
 		return nil
 	}
@@ -105,12 +111,20 @@ func (tb *TokenBalance) UnmarshalJSON(p []byte) error {
 	if err != nil {
 		return err
 	}
-	fe := new(big.Float).SetFloat64(f)
-	tc := new(big.Float).SetFloat64(math.Pow10(int(ti.TokenDecimals)))
-	r := new(big.Float).Mul(fe, tc)
-	z := new(big.Int)
-	result, _ := r.Int(z)
-	tb.Balance = &TokenAmount{result}
-	tb.info = &ti
+	tb.decoded = new(big.Float).SetFloat64(f)
+	tb.Balance = nil
+	tb.info = nil
 	return nil
 }
+
+// setTokenInfo converts the decoded token amount to a balance of the given token
+func (tb *TokenBalance) setTokenInfo(info *TokenInfo) {
+	if tb.decoded == nil {
+		return
+	}
+	tc := new(big.Float).SetFloat64(math.Pow10(int(info.TokenDecimals)))
+	r := new(big.Float).Mul(tb.decoded, tc)
+	result, _ := r.Int(new(big.Int))
+	tb.Balance = &TokenAmount{result}
+	tb.info = info
+}
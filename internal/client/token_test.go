@@ -42,3 +42,28 @@ func TestTokenBalance_Float64_Json(t *testing.T) {
 		t.Errorf("wanted %v, got > %v", "1683563.695", string(j))
 	}
 }
+
+func TestUnmarshalJSON(t *testing.T) {
+	js := []byte(`{"counted": 1.5, "history": {"9": {"counted": 2.25}}, "balance": {"free": 3}}`)
+	done := make(chan CollatorInfo)
+	for _, decimals := range []uint32{18, 12} {
+		go func(decimals uint32) {
+			var info CollatorInfo
+			if err := UnmarshalJSON(js, &info, TokenInfo{TokenDecimals: decimals}); err != nil {
+				t.Error(err)
+			}
+			done <- info
+		}(decimals)
+	}
+	for i := 0; i < 2; i++ {
+		info := <-done
+		history := info.History[9]
+		decimals := len(info.Counted.Balance.AsBigInt().String()) - 1
+		if info.Counted.Float64() != 1.5 || history.Counted.Float64() != 2.25 || info.Balance.Free.Float64() != 3 {
+			t.Errorf("unexpected balances %v %v %v", info.Counted.Float64(), history.Counted.Float64(), info.Balance.Free.Float64())
+		}
+		if expected := int(info.Counted.info.TokenDecimals); decimals != expected {
+			t.Errorf("expected %v decimals, got %v", expected, info.Counted.Balance.AsBigInt())
+		}
+	}
+}
@@ -1,9 +1,56 @@
 package client
 
-var (
-	GlobalTokenInfo TokenInfo
+import (
+	"encoding/json"
+	"reflect"
 )
 
-func InitUnmarshalData(info TokenInfo) {
-	GlobalTokenInfo = info
+var tokenBalanceType = reflect.TypeOf(TokenBalance{})
+
+// UnmarshalJSON decodes JSON holding token balances, balances are converted with the given token info
+func UnmarshalJSON(data []byte, v interface{}, info TokenInfo) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	SetTokenInfo(v, info)
+	return nil
+}
+
+// SetTokenInfo converts the balances decoded in v with the given token info, v must be a pointer
+func SetTokenInfo(v interface{}, info TokenInfo) {
+	setTokenInfo(reflect.ValueOf(v), &info)
+}
+
+func setTokenInfo(v reflect.Value, info *TokenInfo) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			setTokenInfo(v.Elem(), info)
+		}
+	case reflect.Struct:
+		if v.Type() == tokenBalanceType {
+			if v.CanAddr() {
+				v.Addr().Interface().(*TokenBalance).setTokenInfo(info)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				setTokenInfo(v.Field(i), info)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			setTokenInfo(v.Index(i), info)
+		}
+	case reflect.Map:
+		// Map values are not addressable, convert a copy and store it back
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			setTokenInfo(value, info)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
 }
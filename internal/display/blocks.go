@@ -0,0 +1,107 @@
+package display
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"strings"
+	"time"
+)
+
+// BlocksDocument returns block authors of a round followed by a per collator summary, slow blocks are
+// highlighted
+func BlocksDocument(data client.RoundBlocks, c *client.Client, options config.TableOptions, summary bool) Document {
+	names := make(map[string]string)
+	for _, cb := range data.Collators {
+		names[strings.ToLower(cb.Address)] = formatName(cb.Display, cb.Address, options)
+	}
+	if _, ok := names[""]; ok {
+		names[""] = "unknown"
+	}
+	poolName := func(address string, collator string) string {
+		if strings.EqualFold(address, collator) || collator == "" {
+			return ""
+		}
+		if name, ok := names[strings.ToLower(collator)]; ok {
+			return name
+		}
+		return tools.ShortAddress(collator)
+	}
+	formatSecs := func(val interface{}) string {
+		return fmt.Sprintf("%.0fs", val.(float64))
+	}
+	tables := make([]Table, 0, 2)
+	if !summary {
+		averageGap := c.SnapBlock.DurationSecs
+		t := Table{
+			Columns: []TableColumn{
+				{Name: "block", Header: "Block"},
+				{Name: "author_address", Header: "Author", Hidden: true},
+				{Name: "author", Header: "Author"},
+				{Name: "time", Header: "Time"},
+				{
+					Name:   "gap",
+					Header: "Gap",
+					Format: func(val interface{}) string {
+						return fmt.Sprintf("%.1fs", val.(float64))
+					},
+				},
+			},
+			// Slow blocks are highlighted
+			Painter: func(row []interface{}) text.Colors {
+				if row[4].(float64) > averageGap*2 {
+					return text.Colors{text.FgYellow}
+				}
+				return nil
+			},
+		}
+		for _, block := range data.Blocks {
+			t.Rows = append(t.Rows, []interface{}{
+				block.Number,
+				block.Author,
+				names[strings.ToLower(block.Author)],
+				time.UnixMilli(int64(block.TsMillis)).UTC().Format(time.RFC3339),
+				block.GapSecs,
+			})
+		}
+		tables = append(tables, t)
+	}
+	t := Table{
+		Columns: []TableColumn{
+			{Name: "address", Header: "Address", Hidden: true},
+			{Name: "display", Header: "Display"},
+			{Name: "pool", Header: "Pool"},
+			{Name: "blocks", Header: "Blocks"},
+			{Name: "first", Header: "First"},
+			{Name: "last", Header: "Last"},
+			{
+				Name:      "max_gap_blocks",
+				Header:    "Max Gap",
+				SubHeader: "Blocks",
+			},
+			{Name: "max_gap", Header: "Max Gap", SubHeader: "Time", Format: formatSecs},
+			{Name: "avg_gap", Header: "Avg Gap", SubHeader: "Time", Format: formatSecs},
+		},
+	}
+	for _, cb := range data.Collators {
+		t.Rows = append(t.Rows, []interface{}{
+			cb.Address,
+			names[strings.ToLower(cb.Address)],
+			poolName(cb.Address, cb.Collator),
+			cb.Blocks,
+			cb.First,
+			cb.Last,
+			cb.MaxGapBlocks,
+			cb.MaxGapSecs,
+			cb.AvgGapSecs,
+		})
+	}
+	tables = append(tables, t)
+	return Document{
+		Title:  fmt.Sprintf("%v\nRound:%v blocks:#%v-#%v", chainTitle(c), data.Round, data.First, data.Last),
+		Data:   data,
+		Tables: tables,
+	}
+}
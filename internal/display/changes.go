@@ -0,0 +1,72 @@
+package display
+
+import (
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/internal/tools"
+)
+
+// changedCell is a value that changed since a previous table, it is rendered highlighted
+type changedCell struct {
+	value    interface{}
+	previous interface{}
+}
+
+func (cc changedCell) String() string {
+	return formatCell(cc.value)
+}
+
+// colors returns green when the value improved and red when it got worse, other changes are cyan
+func (cc changedCell) colors(inverse bool) text.Colors {
+	current, ok := tools.ToFloat(cc.value)
+	previous, pok := tools.ToFloat(cc.previous)
+	if !ok || !pok {
+		return text.Colors{text.FgCyan, text.Bold}
+	}
+	if (current > previous) != inverse {
+		return text.Colors{text.FgGreen, text.Bold}
+	}
+	return text.Colors{text.FgRed, text.Bold}
+}
+
+// HighlightChanges marks the cells of a table that changed from a previous one, rows are matched by key
+// and columns by name, keys are given in the original row order
+func HighlightChanges(t *Table, keys []string, previous Table, previousKeys []string) int {
+	previousRows := make(map[string][]interface{})
+	for i, key := range previousKeys {
+		if i < len(previous.Rows) {
+			previousRows[key] = previous.Rows[i]
+		}
+	}
+	previousColumns := make(map[string]int)
+	for i, column := range previous.Columns {
+		previousColumns[column.Name] = i
+	}
+	changes := 0
+	for i, key := range keys {
+		if i >= len(t.Rows) {
+			break
+		}
+		prevRow, ok := previousRows[key]
+		if !ok {
+			continue
+		}
+		for j, column := range t.Columns {
+			k, ok := previousColumns[column.Name]
+			if !ok || column.Hidden {
+				continue
+			}
+			if tools.CompareValues(unwrapCell(t.Rows[i][j]), unwrapCell(prevRow[k])) != 0 {
+				t.Rows[i][j] = changedCell{value: t.Rows[i][j], previous: unwrapCell(prevRow[k])}
+				changes++
+			}
+		}
+	}
+	return changes
+}
+
+func unwrapCell(val interface{}) interface{} {
+	if cc, ok := val.(changedCell); ok {
+		return cc.value
+	}
+	return val
+}
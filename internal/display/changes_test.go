@@ -0,0 +1,39 @@
+package display
+
+import (
+	"github.com/jedib0t/go-pretty/v6/text"
+	"testing"
+)
+
+func TestHighlightChanges(t *testing.T) {
+	columns := []TableColumn{{Name: "rank", Inverse: true}, {Name: "blocks"}, {Name: "display"}}
+	previous := Table{
+		Columns: columns,
+		Rows:    [][]interface{}{{uint32(1), uint32(3), "a"}, {uint32(2), uint32(5), "b"}},
+	}
+	current := Table{
+		Columns: columns,
+		Rows:    [][]interface{}{{uint32(1), uint32(6), "b2"}, {uint32(2), uint32(3), "a"}, {uint32(3), uint32(0), "c"}},
+	}
+	changes := HighlightChanges(&current, []string{"0x02", "0x01", "0x03"}, previous, []string{"0x01", "0x02"})
+	if changes != 4 {
+		t.Fatalf("got %v changes, wanted 4", changes)
+	}
+	rank, ok := current.Rows[0][0].(changedCell)
+	if !ok || rank.previous != uint32(2) {
+		t.Fatalf("expected rank change, got %v", current.Rows[0][0])
+	}
+	// Rank going from 2 to 1 is an improvement
+	if c := rank.colors(columns[0].Inverse); c[0] != text.FgGreen {
+		t.Errorf("expected rank up to be green, got %v", c)
+	}
+	if _, ok := current.Rows[1][1].(changedCell); ok {
+		t.Errorf("unchanged cell highlighted")
+	}
+	if _, ok := current.Rows[2][0].(changedCell); ok {
+		t.Errorf("new row highlighted")
+	}
+	if exportValue(current.Rows[0][1]) != "6" {
+		t.Errorf("expected plain export of changed cell")
+	}
+}
@@ -0,0 +1,119 @@
+package display
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"math"
+	"strings"
+)
+
+// barLevels are the unicode eighth blocks used for the top of each bar
+var barLevels = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// asciiLevels replace the eighth blocks in ascii sparklines, from lowest to highest
+var asciiLevels = []rune{'_', '.', ',', '-', '=', '+', '*', '#'}
+
+// Chart draws values as vertical bars scaled between their min and max, with more values than width adjacent
+// values are averaged, ascii charts use '#' for full cells only
+func Chart(values []float64, width int, height int, first string, last string, ascii bool) string {
+	if len(values) == 0 || width <= 0 || height <= 0 {
+		return ""
+	}
+	values = resample(values, width)
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	labels := []string{tools.Humanize(math.Abs(high)), tools.Humanize(math.Abs(low))}
+	for i, v := range []float64{high, low} {
+		if v < 0 {
+			labels[i] = "-" + labels[i]
+		}
+	}
+	labelWidth := tools.Max(len(labels[0]), len(labels[1]))
+	var b strings.Builder
+	for row := height; row > 0; row-- {
+		label := ""
+		switch row {
+		case height:
+			label = labels[0]
+		case 1:
+			label = labels[1]
+		}
+		fmt.Fprintf(&b, "%*v |", labelWidth, label)
+		for _, v := range values {
+			// Flat series are drawn as a single line
+			level := 1.0
+			if high > low {
+				level = (v - low) / (high - low) * float64(height)
+			}
+			// The lowest value still gets a visible bar
+			level = math.Max(level, 1.0/8)
+			fill := level - float64(row-1)
+			switch {
+			case fill >= 1:
+				b.WriteRune(barRune(8, ascii))
+			case fill <= 0:
+				b.WriteRune(' ')
+			default:
+				b.WriteRune(barRune(int(math.Ceil(fill*8)), ascii))
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%*v +%v\n", labelWidth, "", strings.Repeat("-", len(values)))
+	padding := len(values) - len(first) - len(last)
+	if padding < 1 {
+		padding = 1
+	}
+	fmt.Fprintf(&b, "%*v  %v%v%v\n", labelWidth, "", first, strings.Repeat(" ", padding), last)
+	return b.String()
+}
+
+func barRune(eighths int, ascii bool) rune {
+	if ascii {
+		if eighths >= 4 {
+			return '#'
+		}
+		return '.'
+	}
+	return barLevels[eighths]
+}
+
+// resample averages adjacent values so that at most width values are left
+func resample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	result := make([]float64, width)
+	for i := range result {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		sum := 0.0
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		result[i] = sum / float64(end-start)
+	}
+	return result
+}
+
+// Sparkline draws values as a single line of bars scaled between low and high, values out of range are clamped
+func Sparkline(values []float64, low float64, high float64, ascii bool) string {
+	levels := barLevels[1:]
+	if ascii {
+		levels = asciiLevels
+	}
+	var b strings.Builder
+	for _, v := range values {
+		// Flat series are drawn in the middle
+		level := len(levels) / 2
+		if high > low {
+			ratio := math.Max(0, math.Min(1, (v-low)/(high-low)))
+			level = int(math.Round(ratio * float64(len(levels)-1)))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,404 @@
+package display
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// collatorColumn is a column of the collator table, the same columns are used for CSV exports
+type collatorColumn struct {
+	Name      string
+	Header    string
+	SubHeader string
+	// Compact columns are kept in compact tables
+	Compact bool
+	Hidden  bool
+	// Optional columns are only shown when selected
+	Optional bool
+	// Inverse columns are better when lower
+	Inverse bool
+	// Value returns the raw cell value, used for sorting and exports
+	Value func(info *client.CollatorInfo, ctx *columnContext) interface{}
+	// Format renders the raw value in tables
+	Format func(val interface{}) string
+}
+
+type columnContext struct {
+	pool        *client.CollatorPool
+	revokeRound uint32
+	options     config.TableOptions
+}
+
+func newColumnContext(pool *client.CollatorPool, options config.TableOptions) *columnContext {
+	return &columnContext{
+		pool:        pool,
+		revokeRound: pool.RoundNumber + options.RevokeRounds,
+		options:     options,
+	}
+}
+
+// nameCell is a collator name, exports use the full name while tables use the formatted one
+type nameCell struct {
+	full      string
+	formatted string
+}
+
+func (n nameCell) String() string {
+	return n.full
+}
+
+func humanizeFormat(val interface{}) string {
+	return tools.Humanize(val.(float64))
+}
+
+// FormatApr formats an APR fraction as a percentage
+func FormatApr(apr float64) string {
+	return fmt.Sprintf("%.2f%%", apr*100)
+}
+
+func collatorColumns() []collatorColumn {
+	return []collatorColumn{
+		{
+			Name:    "address",
+			Header:  "Address",
+			Compact: true,
+			Hidden:  true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Address
+			},
+		},
+		{
+			Name:    "display",
+			Header:  "Display",
+			Compact: true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return nameCell{full: info.DisplayName(), formatted: displayName(info, ctx.options)}
+			},
+			Format: func(val interface{}) string {
+				return val.(nameCell).formatted
+			},
+		},
+		{
+			Name:    "rank",
+			Header:  "Rank",
+			Compact: true,
+			Inverse: true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Rank
+			},
+		},
+		{
+			Name:    "selected",
+			Header:  "Selected",
+			Compact: true,
+			Hidden:  true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Selected
+			},
+		},
+		{
+			Name:      "counted",
+			Header:    "Counted",
+			SubHeader: "Free",
+			Compact:   true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Counted.Float64()
+			},
+			Format: humanizeFormat,
+		},
+		{
+			Name:      "blocks",
+			Header:    "Blocks",
+			SubHeader: "Now",
+			Compact:   true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.History[ctx.pool.RoundNumber].Blocks
+			},
+		},
+		{
+			Name:      "blocks_avg",
+			Header:    "Blocks",
+			SubHeader: "Avg",
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return float64(info.AverageBlocks())
+			},
+			Format: func(val interface{}) string {
+				return fmt.Sprintf("%.1f", val.(float64))
+			},
+		},
+		{
+			Name:   "balance",
+			Header: "Balance",
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Balance.GetTransferableBalance().Float64()
+			},
+			Format: humanizeFormat,
+		},
+		{
+			Name:      "blocks_trend",
+			Header:    "Blocks",
+			SubHeader: "Trend",
+			Optional:  true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				// The current round is still in progress and would always look like a drop
+				values := make([]float64, 0, len(info.History))
+				high := 0.0
+				for _, round := range sortedRounds(info.History) {
+					if round < ctx.pool.RoundNumber {
+						blocks := float64(info.History[round].Blocks)
+						values = append(values, blocks)
+						high = math.Max(high, blocks)
+					}
+				}
+				return Sparkline(values, 0, high, ctx.options.Ascii)
+			},
+		},
+		{
+			Name:      "rank_trend",
+			Header:    "Rank",
+			SubHeader: "Trend",
+			Optional:  true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				// Ranks are negated so that bars grow when the collator climbs the ranking
+				values := make([]float64, 0, len(info.History))
+				low, high := math.Inf(1), math.Inf(-1)
+				for _, round := range sortedRounds(info.History) {
+					rank := -float64(info.History[round].Rank)
+					values = append(values, rank)
+					low, high = math.Min(low, rank), math.Max(high, rank)
+				}
+				return Sparkline(values, low, high, ctx.options.Ascii)
+			},
+		},
+		{
+			Name:     "min_bond",
+			Header:   "Min Bond",
+			Optional: true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.MinBond.Float64()
+			},
+			Format: humanizeFormat,
+		},
+		{
+			Name:     "apr",
+			Header:   "APR",
+			Optional: true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Apr
+			},
+			Format: func(val interface{}) string {
+				return FormatApr(val.(float64))
+			},
+		},
+		{
+			Name:      "new_counted",
+			Header:    "Revokes",
+			SubHeader: "Counted",
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.RevokeAt(ctx.revokeRound).Counted.Float64()
+			},
+			Format: humanizeFormat,
+		},
+		{
+			Name:      "new_delta",
+			Header:    "Revokes",
+			SubHeader: "Delta",
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Counted.Float64() - info.RevokeAt(ctx.revokeRound).Counted.Float64()
+			},
+			Format: humanizeFormat,
+		},
+		{
+			Name:      "new_rank",
+			Header:    "Revokes",
+			SubHeader: "New Rank",
+			Inverse:   true,
+			Value: func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+				return info.Revokes[ctx.revokeRound].Rank
+			},
+		},
+	}
+}
+
+// roundColumn is a column available for any history or revoke round using name@round
+type roundColumn struct {
+	Header  string
+	Inverse bool
+	History func(h client.CollatorHistory) interface{}
+	Revoke  func(r client.RevokeRound) interface{}
+	Format  func(val interface{}) string
+}
+
+var roundColumns = map[string]roundColumn{
+	"blocks": {
+		Header: "Blocks",
+		History: func(h client.CollatorHistory) interface{} {
+			return h.Blocks
+		},
+	},
+	"counted": {
+		Header: "Counted",
+		History: func(h client.CollatorHistory) interface{} {
+			return h.Counted.Float64()
+		},
+		Revoke: func(r client.RevokeRound) interface{} {
+			return r.Counted.Float64()
+		},
+		Format: humanizeFormat,
+	},
+	"rank": {
+		Header:  "Rank",
+		Inverse: true,
+		History: func(h client.CollatorHistory) interface{} {
+			return h.Rank
+		},
+		Revoke: func(r client.RevokeRound) interface{} {
+			return r.Rank
+		},
+	},
+}
+
+// selectColumns returns the named columns in order, default columns are returned if no name is given
+func selectColumns(names []string, pool *client.CollatorPool) ([]collatorColumn, error) {
+	result := make([]collatorColumn, 0)
+	if len(names) == 0 {
+		for _, column := range collatorColumns() {
+			if !column.Optional {
+				result = append(result, column)
+			}
+		}
+		return result, nil
+	}
+	for _, name := range names {
+		column, err := findColumn(name, pool)
+		if err != nil {
+			return result, err
+		}
+		// Selected columns are always shown
+		column.Hidden = false
+		column.Compact = true
+		result = append(result, column)
+	}
+	return result, nil
+}
+
+// findColumn returns a column by name ignoring case, spaces and underscores, a name can be suffixed by
+// @round where round is absolute or relative to the current round when signed (blocks@-1, counted@+7)
+func findColumn(name string, pool *client.CollatorPool) (collatorColumn, error) {
+	key := normalizeColumnName(name)
+	if base, spec, ok := strings.Cut(key, "@"); ok {
+		return findRoundColumn(base, spec, pool)
+	}
+	for _, column := range collatorColumns() {
+		if key == normalizeColumnName(column.Name) {
+			return column, nil
+		}
+	}
+	return collatorColumn{}, fmt.Errorf("unknown column '%v'", name)
+}
+
+func findRoundColumn(base string, spec string, pool *client.CollatorPool) (collatorColumn, error) {
+	rc, ok := roundColumns[base]
+	if !ok {
+		return collatorColumn{}, fmt.Errorf("column '%v' is not available per round", base)
+	}
+	round, err := parseRound(spec, pool.RoundNumber)
+	if err != nil {
+		return collatorColumn{}, err
+	}
+	column := collatorColumn{
+		Name:      fmt.Sprintf("%v@%v", base, round),
+		Header:    rc.Header,
+		SubHeader: fmt.Sprintf("#%v", round),
+		Inverse:   rc.Inverse,
+		Format:    rc.Format,
+	}
+	if round <= pool.RoundNumber {
+		if !poolHasRound(pool, round, false) {
+			return column, fmt.Errorf("round %v is not in history, increase history rounds", round)
+		}
+		column.Value = func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+			return rc.History(info.History[round])
+		}
+		return column, nil
+	}
+	if rc.Revoke == nil {
+		return column, fmt.Errorf("column '%v' is not available for future rounds", base)
+	}
+	if !poolHasRound(pool, round, true) {
+		return column, fmt.Errorf("round %v is not in revoke rounds", round)
+	}
+	column.Value = func(info *client.CollatorInfo, ctx *columnContext) interface{} {
+		return rc.Revoke(info.Revokes[round])
+	}
+	return column, nil
+}
+
+// parseRound returns the absolute round of a column suffix, signed values are relative
+func parseRound(spec string, current uint32) (uint32, error) {
+	value, err := strconv.ParseInt(spec, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid round '%v'", spec)
+	}
+	if strings.HasPrefix(spec, "-") || strings.HasPrefix(spec, "+") || value == 0 {
+		value += int64(current)
+	}
+	if value < 0 || value > math.MaxUint32 {
+		return 0, fmt.Errorf("invalid round '%v'", spec)
+	}
+	return uint32(value), nil
+}
+
+func poolHasRound(pool *client.CollatorPool, round uint32, revoke bool) bool {
+	for _, info := range pool.Collators {
+		if _, ok := info.History[round]; ok && !revoke {
+			return true
+		}
+		if _, ok := info.Revokes[round]; ok && revoke {
+			return true
+		}
+	}
+	return len(pool.Collators) == 0
+}
+
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", " ", "").Replace(name))
+}
+
+// tableColumn returns the renderer column
+func (cc *collatorColumn) tableColumn(options config.TableOptions) TableColumn {
+	return TableColumn{
+		Name:      cc.Name,
+		Header:    cc.Header,
+		SubHeader: cc.SubHeader,
+		Hidden:    cc.Hidden || (options.Compact && !cc.Compact),
+		Inverse:   cc.Inverse,
+		Format:    cc.Format,
+	}
+}
+
+// columnIndex returns the position of a named column, -1 if missing
+func columnIndex(columns []TableColumn, name string) int {
+	for i, column := range columns {
+		if column.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// exportValue renders a raw value for exports, floats are rounded to 6 decimals
+func exportValue(val interface{}) string {
+	switch v := unwrapCell(val).(type) {
+	case float64:
+		s := strconv.FormatFloat(v, 'f', 6, 64)
+		return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,126 @@
+package display
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"testing"
+)
+
+func testPool() client.CollatorPool {
+	return client.CollatorPool{
+		SelectedSize: 2,
+		RoundNumber:  10,
+		Collators: []client.CollatorInfo{
+			{
+				Address:  "0x01",
+				Selected: true,
+				Rank:     1,
+				History: map[uint32]client.CollatorHistory{
+					9:  {Rank: 1, Blocks: 5},
+					10: {Rank: 1, Blocks: 2},
+				},
+				Revokes: map[uint32]client.RevokeRound{10: {Rank: 1}, 11: {Rank: 2}},
+			},
+			{
+				Address:  "0x02",
+				Selected: true,
+				Rank:     2,
+				History: map[uint32]client.CollatorHistory{
+					9:  {Rank: 2, Blocks: 8},
+					10: {Rank: 2, Blocks: 1},
+				},
+				Revokes: map[uint32]client.RevokeRound{10: {Rank: 2}, 11: {Rank: 1}},
+			},
+		},
+	}
+}
+
+func TestParseRound(t *testing.T) {
+	cases := map[string]uint32{"-1": 9, "+1": 11, "0": 10, "5": 5}
+	for spec, expected := range cases {
+		round, err := parseRound(spec, 10)
+		if err != nil || round != expected {
+			t.Errorf("got %v %v for %v, wanted %v", round, err, spec, expected)
+		}
+	}
+	if _, err := parseRound("-11", 10); err == nil {
+		t.Errorf("expected error on negative round")
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	pool := testPool()
+	columns, err := selectColumns([]string{"Display", "blocks@-1", "rank@+1", "new_rank"}, &pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"display", "blocks@9", "rank@11", "new_rank"}
+	for i, name := range names {
+		if columns[i].Name != name {
+			t.Errorf("got %v, wanted %v", columns[i].Name, name)
+		}
+	}
+	if v := columns[1].Value(&pool.Collators[1], nil); v != uint32(8) {
+		t.Errorf("got %v blocks, wanted 8", v)
+	}
+	for _, invalid := range []string{"unknown", "blocks@+1", "blocks@-5", "balance@-1"} {
+		if _, err := selectColumns([]string{invalid}, &pool); err == nil {
+			t.Errorf("expected error for column %v", invalid)
+		}
+	}
+}
+
+func TestCollatorsTableSort(t *testing.T) {
+	pool := testPool()
+	options := config.GetDefaultTableOptions()
+	options.Columns = []string{"display", "rank"}
+	options.SortKey = "blocks@-1"
+	options.SortDesc = true
+	table, err := CollatorsTable(pool, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Columns) != 3 || !table.Columns[2].Hidden || table.Sort[0].Number != 3 {
+		t.Fatalf("expected hidden sort column, got %+v", table.Columns)
+	}
+	if rows := table.sortedRows(); rows[0][1] != uint32(2) {
+		t.Errorf("expected rank 2 first, got %v", rows[0][1])
+	}
+}
+
+func TestCollatorsTableMultipleSort(t *testing.T) {
+	pool := testPool()
+	options := config.GetDefaultTableOptions()
+	options.Columns = []string{"display", "rank"}
+	options.Sort = []string{"-blocks@0", "rank"}
+	table, err := CollatorsTable(pool, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Sort) != 2 || !table.Sort[0].Desc || table.Sort[1].Number != 2 {
+		t.Fatalf("unexpected sort %+v", table.Sort)
+	}
+	if rows := table.sortedRows(); rows[0][1] != uint32(1) {
+		t.Errorf("expected rank 1 first, got %v", rows[0][1])
+	}
+	options.Sort = []string{"-unknown"}
+	if _, err := CollatorsTable(pool, options); err == nil {
+		t.Errorf("expected error for unknown sort column")
+	}
+}
+
+func TestTrendColumns(t *testing.T) {
+	pool := testPool()
+	pool.Collators[0].History[8] = client.CollatorHistory{Rank: 2, Blocks: 0}
+	options := config.GetDefaultTableOptions()
+	options.Ascii = true
+	options.Columns = []string{"blocks_trend", "rank_trend"}
+	table, err := CollatorsTable(pool, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The current round is left out of blocks, ranks grow when climbing
+	if row := table.sortedRows()[0]; row[0] != "_#" || row[1] != "_##" {
+		t.Errorf("unexpected trends %v", row)
+	}
+}
@@ -0,0 +1,130 @@
+package display
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"golang.org/x/exp/slices"
+)
+
+// CollatorsCsvDocument returns the collator ranking with the same columns as the table, or one row per
+// collator per history and revoke round
+func CollatorsCsvDocument(data client.CollatorPool, options config.CsvOptions) (Document, error) {
+	result := Document{Data: data}
+	if options.PerRound {
+		result.Tables = []Table{perRoundTable(data)}
+		return result, nil
+	}
+	t, err := CollatorsTable(data, config.TableOptions{
+		RevokeRounds: options.RevokeRounds,
+		Columns:      options.Columns,
+	})
+	result.Tables = []Table{t}
+	return result, err
+}
+
+// perRoundTable returns one row per collator per history round and per revoke round
+func perRoundTable(data client.CollatorPool) Table {
+	t := Table{Columns: namedColumns(
+		"address", "display", "type", "round", "rank", "counted", "blocks", "selected", "expected", "revoked",
+	)}
+	for _, info := range data.Collators {
+		for _, round := range sortedRounds(info.History) {
+			h := info.History[round]
+			t.Rows = append(t.Rows, []interface{}{
+				info.Address,
+				info.DisplayName(),
+				"history",
+				round,
+				h.Rank,
+				h.Counted.Float64(),
+				h.Blocks,
+				h.Selected,
+				float64(h.Expected),
+				"",
+			})
+		}
+		for _, round := range sortedRounds(info.Revokes) {
+			r := info.Revokes[round]
+			t.Rows = append(t.Rows, []interface{}{
+				info.Address,
+				info.DisplayName(),
+				"revoke",
+				round,
+				r.Rank,
+				r.Counted.Float64(),
+				"",
+				"",
+				"",
+				r.Amount.Float64(),
+			})
+		}
+	}
+	return t
+}
+
+type delegationData struct {
+	Collator     string              `json:"collator"`
+	Display      string              `json:"display"`
+	Delegator    string              `json:"delegator"`
+	Amount       client.TokenBalance `json:"amount"`
+	RevokeAmount client.TokenBalance `json:"revoke_amount"`
+	RevokeReason string              `json:"revoke_reason,omitempty"`
+	RevokeRound  uint32              `json:"revoke_round,omitempty"`
+}
+
+// DelegationsDocument returns one row per delegation
+func DelegationsDocument(data client.CollatorPool) Document {
+	t := Table{Columns: namedColumns(
+		"collator", "display", "delegator", "amount", "revoke_amount", "revoke_reason", "revoke_round",
+	)}
+	records := make([]delegationData, 0)
+	for _, info := range data.Collators {
+		for _, delegation := range info.Delegations {
+			record := delegationData{
+				Collator:     info.Address,
+				Display:      info.DisplayName(),
+				Delegator:    delegation.Address,
+				Amount:       delegation.Amount,
+				RevokeAmount: delegation.RevokeAmount,
+				RevokeReason: delegation.RevokeReason,
+				RevokeRound:  delegation.RevokeRound,
+			}
+			records = append(records, record)
+			var revokeRound interface{} = ""
+			if delegation.RevokeRound > 0 {
+				revokeRound = delegation.RevokeRound
+			}
+			t.Rows = append(t.Rows, []interface{}{
+				record.Collator,
+				record.Display,
+				record.Delegator,
+				record.Amount.Float64(),
+				record.RevokeAmount.Float64(),
+				record.RevokeReason,
+				revokeRound,
+			})
+		}
+	}
+	for _, i := range []int{3, 4} {
+		t.Columns[i].Format = humanizeFormat
+	}
+	return Document{Data: records, Tables: []Table{t}}
+}
+
+// namedColumns returns columns whose header is the name itself
+func namedColumns(names ...string) []TableColumn {
+	columns := make([]TableColumn, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, TableColumn{Name: name, Header: name})
+	}
+	return columns
+}
+
+func sortedRounds[T any](m map[uint32]T) []uint32 {
+	rounds := make([]uint32, 0, len(m))
+	for round := range m {
+		rounds = append(rounds, round)
+	}
+	slices.Sort(rounds)
+	return rounds
+}
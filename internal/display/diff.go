@@ -0,0 +1,152 @@
+package display
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"time"
+)
+
+// DiffDocument returns the collators that changed between two snap points followed by delegation changes
+func DiffDocument(diff client.PoolDiff, chain string, options config.TableOptions) Document {
+	signedFormat := func(val interface{}) string {
+		switch v := val.(type) {
+		case int64:
+			if v > 0 {
+				return fmt.Sprintf("+%v", v)
+			}
+			return fmt.Sprintf("%v", v)
+		case float64:
+			if v > 0 {
+				return "+" + tools.Humanize(v)
+			} else if v < 0 {
+				return "-" + tools.Humanize(-v)
+			}
+			return "0"
+		}
+		return formatCell(val)
+	}
+	rankFormat := func(val interface{}) string {
+		if val.(uint32) == 0 {
+			return "-"
+		}
+		return formatCell(val)
+	}
+	arrow := " → "
+	if options.Ascii {
+		arrow = " -> "
+	}
+	collators := Table{
+		Columns: []TableColumn{
+			{Name: "address", Header: "Address", Hidden: true},
+			{Name: "display", Header: "Display"},
+			{Name: "pool", Header: "Pool"},
+			{Name: "rank_from", Header: "Rank", SubHeader: "From", Format: rankFormat},
+			{Name: "rank_to", Header: "Rank", SubHeader: "To", Format: rankFormat},
+			{Name: "rank_delta", Header: "Rank", SubHeader: "Delta", Format: signedFormat},
+			{Name: "counted_from", Header: "Counted", SubHeader: "From", Hidden: options.Compact, Format: humanizeFormat},
+			{Name: "counted_to", Header: "Counted", SubHeader: "To", Format: humanizeFormat},
+			{Name: "counted_delta", Header: "Counted", SubHeader: "Delta", Format: signedFormat},
+			{Name: "selection", Header: "Selection"},
+			{Name: "identity", Header: "Identity"},
+			{Name: "delegations", Header: "Delegations", SubHeader: "Changes"},
+		},
+		Painter: func(row []interface{}) text.Colors {
+			switch row[9] {
+			case "entered":
+				return text.Colors{text.FgGreen}
+			case "exited":
+				return text.Colors{text.FgRed}
+			}
+			if row[2] == "left" {
+				return text.Colors{text.FgHiBlack}
+			}
+			return nil
+		},
+		Width: options.GetTableWidth(),
+	}
+	changes := Table{
+		Title: fmt.Sprintf("Delegation changes of at least %v", tools.Humanize(diff.Threshold)),
+		Columns: []TableColumn{
+			{Name: "collator", Header: "Collator", Hidden: true},
+			{Name: "display", Header: "Display"},
+			{Name: "delegator", Header: "Delegator"},
+			{Name: "change", Header: "Change"},
+			{Name: "from", Header: "Amount", SubHeader: "From", Format: humanizeFormat},
+			{Name: "to", Header: "Amount", SubHeader: "To", Format: humanizeFormat},
+			{Name: "delta", Header: "Amount", SubHeader: "Delta", Format: signedFormat},
+		},
+		Width: options.GetTableWidth(),
+	}
+	for _, cd := range diff.Collators {
+		name := formatName(cd.Display, cd.Address, options)
+		identity := ""
+		if cd.Identity != nil {
+			identity = identityText(&cd.Identity.From, options) + arrow + identityText(&cd.Identity.To, options)
+		}
+		delegations := ""
+		if len(cd.Delegations) > 0 {
+			delegations = fmt.Sprintf("%v", len(cd.Delegations))
+		}
+		collators.Rows = append(collators.Rows, []interface{}{
+			cd.Address,
+			name,
+			cd.Pool,
+			cd.RankFrom,
+			cd.RankTo,
+			cd.RankDelta(),
+			cd.CountedFrom,
+			cd.CountedTo,
+			cd.CountedDelta,
+			cd.Selection,
+			identity,
+			delegations,
+		})
+		for _, dc := range cd.Delegations {
+			changes.Rows = append(changes.Rows, []interface{}{
+				cd.Address,
+				name,
+				dc.Delegator,
+				dc.Change,
+				dc.From,
+				dc.To,
+				dc.Delta,
+			})
+		}
+	}
+	tables := []Table{collators}
+	if len(changes.Rows) > 0 {
+		tables = append(tables, changes)
+	}
+	return Document{
+		Title:  fmt.Sprintf("Chain:%v %v%v%v", chain, diffPointText(diff.From), arrow, diffPointText(diff.To)),
+		Data:   diff,
+		Tables: tables,
+	}
+}
+
+func diffPointText(point client.DiffPoint) string {
+	return fmt.Sprintf(
+		"round:%v block:#%v (%v)",
+		point.Round.Number,
+		point.Block.Number,
+		time.UnixMilli(int64(point.Block.TsMillis)).UTC().Format("2006-01-02 15:04"),
+	)
+}
+
+func identityText(identity *client.AccountIdentity, options config.TableOptions) string {
+	// Sub identities already include the parent name
+	name := "none"
+	if identity.Display != "" {
+		name = formatName(identity.Display, "", options)
+	}
+	if identity.Verified {
+		if options.Ascii {
+			return name + " (v)"
+		}
+		return name + " ✓"
+	}
+	return name
+}
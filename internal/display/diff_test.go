@@ -0,0 +1,45 @@
+package display
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"strings"
+	"testing"
+)
+
+func TestDiffDocument(t *testing.T) {
+	diff := client.PoolDiff{
+		Threshold: 1000,
+		Collators: []client.CollatorDiff{
+			{
+				Address:      "0x01",
+				Display:      "Zooper",
+				RankFrom:     3,
+				RankTo:       1,
+				CountedDelta: 1500,
+				Selection:    "entered",
+				Identity: &client.IdentityChange{
+					From: client.AccountIdentity{Display: "Zooper"},
+					To:   client.AccountIdentity{Display: "Zooper", Verified: true},
+				},
+				Delegations: []client.DelegationChange{{Delegator: "0xd1", Change: "new", To: 1500, Delta: 1500}},
+			},
+			{Address: "0x02", Pool: "left", RankFrom: 2},
+		},
+	}
+	options := config.GetDefaultTableOptions()
+	options.Ascii = true
+	doc := DiffDocument(diff, "Moonbeam", options)
+	if len(doc.Tables) != 2 || len(doc.Tables[1].Rows) != 1 {
+		t.Fatalf("expected collators and delegation tables, got %v", len(doc.Tables))
+	}
+	out, err := RenderString("table", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"+2", "+1.50K", "entered", "Zooper -> Zooper (v)", "left", "0xd1"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %v in\n%v", expected, out)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package display
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+)
+
+type historyData struct {
+	Round       uint32  `json:"round"`
+	Rank        uint32  `json:"rank"`
+	Counted     float64 `json:"counted"`
+	Blocks      uint32  `json:"blocks"`
+	Expected    float64 `json:"expected"`
+	Delegations uint32  `json:"delegations"`
+	Selected    bool    `json:"selected"`
+	Threshold   float64 `json:"threshold"`
+	// Distance is the counted stake above the selection threshold, negative when below
+	Distance float64 `json:"distance"`
+}
+
+// HistoryMetrics lists the metrics that can be charted
+var HistoryMetrics = []string{"rank", "counted", "blocks", "delegations", "threshold", "distance"}
+
+// HistoryDocument returns the per round time series of a collator, oldest round first
+func HistoryDocument(address string, name string, history map[uint32]client.CollatorHistory, options config.TableOptions) Document {
+	t := Table{
+		Columns: []TableColumn{
+			{Name: "round", Header: "Round"},
+			{Name: "rank", Header: "Rank", Inverse: true},
+			{Name: "counted", Header: "Counted", Format: humanizeFormat},
+			{Name: "blocks", Header: "Blocks", SubHeader: "Produced"},
+			{
+				Name:      "expected",
+				Header:    "Blocks",
+				SubHeader: "Expected",
+				Hidden:    options.Compact,
+				Format: func(val interface{}) string {
+					return fmt.Sprintf("%.1f", val.(float64))
+				},
+			},
+			{Name: "delegations", Header: "Delegations"},
+			{Name: "selected", Header: "Selected"},
+			{Name: "threshold", Header: "Threshold", Hidden: options.Compact, Format: humanizeFormat},
+			{
+				Name:   "distance",
+				Header: "Distance",
+				Format: func(val interface{}) string {
+					if v := val.(float64); v < 0 {
+						return "-" + humanizeFormat(-v)
+					}
+					return humanizeFormat(val)
+				},
+			},
+		},
+		// Rounds out of the selected set are dimmed, selected rounds without blocks are outages
+		Painter: func(row []interface{}) text.Colors {
+			if !row[6].(bool) {
+				return text.Colors{text.FgHiBlack}
+			} else if row[3].(uint32) == 0 {
+				return text.Colors{text.FgRed}
+			}
+			return nil
+		},
+		Width: options.GetTableWidth(),
+	}
+	records := historyRecords(history)
+	for _, r := range records {
+		t.Rows = append(t.Rows, []interface{}{
+			r.Round,
+			r.Rank,
+			r.Counted,
+			r.Blocks,
+			r.Expected,
+			r.Delegations,
+			r.Selected,
+			r.Threshold,
+			r.Distance,
+		})
+	}
+	title := fmt.Sprintf("Collator %v (%v)", formatName(name, address, options), address)
+	if len(records) > 0 {
+		title = fmt.Sprintf("%v rounds %v-%v", title, records[0].Round, records[len(records)-1].Round)
+	}
+	return Document{
+		Title:  title,
+		Data:   records,
+		Tables: []Table{t},
+	}
+}
+
+// HistoryChart draws a metric across history rounds
+func HistoryChart(history map[uint32]client.CollatorHistory, metric string, height int, options config.TableOptions) (string, error) {
+	records := historyRecords(history)
+	if len(records) == 0 {
+		return "", nil
+	}
+	values := make([]float64, 0, len(records))
+	for _, r := range records {
+		switch metric {
+		case "rank":
+			values = append(values, float64(r.Rank))
+		case "counted":
+			values = append(values, r.Counted)
+		case "blocks":
+			values = append(values, float64(r.Blocks))
+		case "delegations":
+			values = append(values, float64(r.Delegations))
+		case "threshold":
+			values = append(values, r.Threshold)
+		case "distance":
+			values = append(values, r.Distance)
+		default:
+			return "", fmt.Errorf("unknown metric '%v', expecting one of %v", metric, HistoryMetrics)
+		}
+	}
+	title := metric
+	if metric == "rank" {
+		title = "rank (lower is better)"
+	}
+	chart := Chart(
+		values,
+		options.GetTableWidth()-12,
+		height,
+		fmt.Sprintf("%v", records[0].Round),
+		fmt.Sprintf("%v", records[len(records)-1].Round),
+		options.Ascii,
+	)
+	return fmt.Sprintf("%v\n%v", title, chart), nil
+}
+
+func historyRecords(history map[uint32]client.CollatorHistory) []historyData {
+	records := make([]historyData, 0, len(history))
+	for _, round := range sortedRounds(history) {
+		h := history[round]
+		counted, threshold := h.Counted.Float64(), h.Threshold.Float64()
+		records = append(records, historyData{
+			Round:       round,
+			Rank:        h.Rank,
+			Counted:     counted,
+			Blocks:      h.Blocks,
+			Expected:    float64(h.Expected),
+			Delegations: h.Delegations,
+			Selected:    h.Selected,
+			Threshold:   threshold,
+			Distance:    counted - threshold,
+		})
+	}
+	return records
+}
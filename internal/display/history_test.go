@@ -0,0 +1,51 @@
+package display
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"strings"
+	"testing"
+)
+
+func TestChart(t *testing.T) {
+	out := Chart([]float64{0, 4, 8}, 10, 2, "1", "3", true)
+	expected := "8 |  #\n0 |.##\n  +---\n   1 3\n"
+	if out != expected {
+		t.Fatalf("unexpected chart\n%v", out)
+	}
+	if got := resample([]float64{1, 3, 5, 7}, 2); got[0] != 2 || got[1] != 6 {
+		t.Errorf("unexpected resample %v", got)
+	}
+}
+
+func TestHistoryDocument(t *testing.T) {
+	pool := testPool()
+	history := pool.Collators[1].History
+	doc := HistoryDocument("0x02", "Zooper", history, config.GetDefaultTableOptions())
+	records := doc.Data.([]historyData)
+	if len(records) != 2 || records[0].Round != 9 || records[1].Blocks != 1 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if !strings.HasSuffix(doc.Title, "rounds 9-10") {
+		t.Errorf("unexpected title %v", doc.Title)
+	}
+	if _, err := HistoryChart(history, "stake", 5, config.GetDefaultTableOptions()); err == nil {
+		t.Errorf("expected unknown metric error")
+	}
+	chart, err := HistoryChart(map[uint32]client.CollatorHistory{1: {Rank: 3}, 2: {Rank: 5}}, "rank", 2, config.GetDefaultTableOptions())
+	if err != nil || !strings.HasPrefix(chart, "rank (lower is better)\n5 |") {
+		t.Errorf("unexpected chart %v %v", chart, err)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 2, 4, 8}, 0, 8, false); got != "▁▃▅█" {
+		t.Errorf("got %v", got)
+	}
+	if got := Sparkline([]float64{0, 4, 8, 12}, 0, 8, true); got != "_=##" {
+		t.Errorf("got %v", got)
+	}
+	if got := Sparkline([]float64{3, 3}, 3, 3, true); got != "==" {
+		t.Errorf("got %v for flat series", got)
+	}
+}
@@ -0,0 +1,71 @@
+package display
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"strings"
+	"time"
+)
+
+type infoData struct {
+	Metadata *client.Client       `json:"info"`
+	Accounts []client.AccountInfo `json:"accounts,omitempty"`
+}
+
+// InfoDocument returns chain info as a key value table followed by accounts if any
+func InfoDocument(c *client.Client, accounts []client.AccountInfo, options config.TableOptions) Document {
+	chain := Table{
+		Columns: []TableColumn{
+			{Name: "key", Header: "Key"},
+			{Name: "value", Header: "Value"},
+		},
+		Rows: [][]interface{}{
+			{"endpoint", c.RpcUrl},
+			{"chain", c.Chain},
+			{"spec", c.SpecVersion},
+			{"block", c.SnapBlock.Number},
+			{"hash", c.SnapBlock.Hash.Hex()},
+			{"time", time.UnixMilli(int64(c.SnapBlock.TsMillis)).UTC().Format(time.RFC3339)},
+			{"block_duration", fmt.Sprintf("%.2fs", c.SnapBlock.DurationSecs)},
+			{"round", c.SnapRound.Number},
+			{"round_length", c.SnapRound.Length},
+			{"round_start", c.SnapRound.Start},
+			{"revoke_delay", c.SnapRound.RevokeDelay},
+			{"selected", c.SnapStaking.Selected},
+			{"candidates", c.SnapStaking.Total},
+			{"token", fmt.Sprintf("%v (%v decimals)", c.TokenInfo.TokenSymbol, c.TokenInfo.TokenDecimals)},
+		},
+	}
+	tables := []Table{chain}
+	if len(accounts) > 0 {
+		t := Table{
+			Title: "Accounts",
+			Columns: []TableColumn{
+				{Name: "address", Header: "Address"},
+				{Name: "display", Header: "Display"},
+				{Name: "verified", Header: "Verified"},
+				{Name: "free", Header: "Free", Format: humanizeFormat},
+				{Name: "reserved", Header: "Reserved", Format: humanizeFormat},
+				{Name: "frozen", Header: "Frozen", Format: humanizeFormat},
+				{Name: "transferable", Header: "Transferable", Format: humanizeFormat},
+			},
+		}
+		for _, account := range accounts {
+			t.Rows = append(t.Rows, []interface{}{
+				account.Address,
+				strings.TrimSpace(formatName(account.Identity.Display, account.Address, options)),
+				account.Identity.Verified,
+				account.Balance.Free.Float64(),
+				account.Balance.Reserved.Float64(),
+				account.Balance.Frozen.Float64(),
+				account.Balance.GetTransferableBalance().Float64(),
+			})
+		}
+		tables = append(tables, t)
+	}
+	return Document{
+		Data:   infoData{Metadata: c, Accounts: accounts},
+		Tables: tables,
+	}
+}
@@ -0,0 +1,41 @@
+package display
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"strings"
+)
+
+// OperatorDocument returns the operator check report, one row per check
+func OperatorDocument(report client.OperatorReport, options config.TableOptions) Document {
+	status := "healthy"
+	if !report.Healthy() {
+		status = "unhealthy"
+	}
+	authorIds := make([]string, 0, len(report.AuthorIds))
+	for _, mapping := range report.AuthorIds {
+		authorIds = append(authorIds, mapping.NimbusId)
+	}
+	t := Table{
+		Columns: []TableColumn{
+			{Name: "key", Header: "Key"},
+			{Name: "value", Header: "Value"},
+		},
+		Rows: [][]interface{}{
+			{"address", report.Address},
+			{"display", formatName(report.Display, report.Address, options)},
+			{"candidate", report.Candidate},
+			{"selected", report.Selected},
+			{"rank", report.Rank},
+			{"author_ids", strings.Join(authorIds, " ")},
+			{"round", report.Round},
+			{"round_blocks", report.RoundBlocks},
+			{"blocks", report.Blocks},
+			{"status", status},
+		},
+	}
+	for _, issue := range report.Issues {
+		t.Rows = append(t.Rows, []interface{}{"issue", issue})
+	}
+	return Document{Data: report, Tables: []Table{t}}
+}
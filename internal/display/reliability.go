@@ -0,0 +1,99 @@
+package display
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+)
+
+type reliabilityData struct {
+	Address     string                     `json:"address"`
+	Display     string                     `json:"display"`
+	Rank        uint32                     `json:"rank"`
+	Selected    bool                       `json:"selected"`
+	Reliability client.CollatorReliability `json:"reliability"`
+}
+
+// ReliabilityDocument returns expected vs produced blocks per collator, outages are highlighted
+func ReliabilityDocument(data client.CollatorPool, c *client.Client, options config.TableOptions) Document {
+	t := Table{
+		Columns: []TableColumn{
+			{Name: "address", Header: "Address", Hidden: true},
+			{
+				Name:   "display",
+				Header: "Display",
+				Format: func(val interface{}) string {
+					return val.(nameCell).formatted
+				},
+			},
+			{Name: "rank", Header: "Rank"},
+			{Name: "selected", Header: "Selected", Hidden: true},
+			{Name: "selected_rounds", Header: "Rounds", SubHeader: "Selected"},
+			{Name: "idle_rounds", Header: "Rounds", SubHeader: "Idle", Hidden: options.Compact},
+			{
+				Name:      "expected",
+				Header:    "Blocks",
+				SubHeader: "Expected",
+				Hidden:    options.Compact,
+				Format: func(val interface{}) string {
+					return fmt.Sprintf("%.1f", val.(float64))
+				},
+			},
+			{Name: "produced", Header: "Blocks", SubHeader: "Produced"},
+			{
+				Name:   "ratio",
+				Header: "Ratio",
+				Format: func(val interface{}) string {
+					return fmt.Sprintf("%.2f", val.(float64))
+				},
+			},
+			{Name: "zero_rounds", Header: "Zero", SubHeader: "Rounds"},
+			{Name: "longest", Header: "Zero", SubHeader: "Longest", Hidden: options.Compact},
+			{Name: "current", Header: "Zero", SubHeader: "Current"},
+		},
+		Width: options.GetTableWidth(),
+	}
+	records := make([]reliabilityData, 0, len(data.Collators))
+	for _, info := range data.Collators {
+		r := info.Reliability
+		records = append(records, reliabilityData{
+			Address:     info.Address,
+			Display:     info.DisplayName(),
+			Rank:        info.Rank,
+			Selected:    info.Selected,
+			Reliability: r,
+		})
+		t.Rows = append(t.Rows, []interface{}{
+			info.Address,
+			nameCell{full: info.DisplayName(), formatted: displayName(&info, options)},
+			info.Rank,
+			info.Selected,
+			r.SelectedRounds,
+			r.IdleRounds,
+			float64(r.Expected),
+			r.Blocks,
+			float64(r.Ratio),
+			r.ZeroRounds,
+			r.LongestStreak,
+			r.CurrentStreak,
+		})
+	}
+	// Highlight outages
+	t.Painter = func(row []interface{}) text.Colors {
+		if !row[3].(bool) {
+			return text.Colors{text.FgHiBlack}
+		} else if row[11].(uint32) > 0 {
+			return text.Colors{text.FgRed}
+		} else if row[9].(uint32) > 0 {
+			return text.Colors{text.FgYellow}
+		}
+		return nil
+	}
+	t.Sort = getSortColumns(t.Columns, options)
+	return Document{
+		Title:  chainTitle(c),
+		Data:   records,
+		Tables: []Table{t},
+	}
+}
@@ -0,0 +1,306 @@
+package display
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"gopkg.in/yaml.v3"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Document is the output of a command, Data is used by structured formats (json, yaml) while
+// Tables are used by tabular formats (table, csv, tsv, markdown)
+type Document struct {
+	Title  string
+	Data   interface{}
+	Tables []Table
+}
+
+// Table is a list of rows holding raw values, columns format values for display
+type Table struct {
+	Title   string
+	Columns []TableColumn
+	Rows    [][]interface{}
+	// Painter optionally colors rows in terminal tables
+	Painter func(row []interface{}) text.Colors
+	// Sort lists the sort columns by priority, rows are kept in order if empty
+	Sort  []SortColumn
+	Width int
+}
+
+// SortColumn is a column used to sort rows, Number starts from 1
+type SortColumn struct {
+	Number int
+	Desc   bool
+}
+
+type TableColumn struct {
+	// Name is used as header in csv exports
+	Name      string
+	Header    string
+	SubHeader string
+	// Hidden columns are only exported in csv
+	Hidden bool
+	// Inverse columns are better when lower, such as ranks
+	Inverse bool
+	Format  func(val interface{}) string
+}
+
+type Renderer interface {
+	Render(w io.Writer, doc Document) error
+}
+
+// OutputFormats lists the supported renderer formats
+var OutputFormats = []string{"json", "yaml", "table", "csv", "tsv", "markdown"}
+
+// NewRenderer returns the renderer for a given output format
+func NewRenderer(format string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return jsonRenderer{}, nil
+	case "yaml", "yml":
+		return yamlRenderer{}, nil
+	case "table":
+		return tableRenderer{}, nil
+	case "csv":
+		return csvRenderer{separator: ','}, nil
+	case "tsv":
+		return csvRenderer{separator: '\t'}, nil
+	case "markdown", "md":
+		return markdownRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output '%v', expecting one of %v", format, OutputFormats)
+	}
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, doc Document) error {
+	if doc.Data == nil {
+		return fmt.Errorf("json output is not supported")
+	}
+	b, err := json.MarshalIndent(doc.Data, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+type yamlRenderer struct{}
+
+// Render converts the json representation to yaml so that json tags and marshallers are honored
+func (yamlRenderer) Render(w io.Writer, doc Document) error {
+	if doc.Data == nil {
+		return fmt.Errorf("yaml output is not supported")
+	}
+	b, err := json.Marshal(doc.Data)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return err
+	}
+	clearYamlStyle(&node)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearYamlStyle drops the flow and quoting style inherited from json so the output is block yaml
+func clearYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYamlStyle(child)
+	}
+}
+
+type tableRenderer struct{}
+
+func (tableRenderer) Render(w io.Writer, doc Document) error {
+	if len(doc.Tables) == 0 {
+		return fmt.Errorf("table output is not supported")
+	}
+	if doc.Title != "" {
+		fmt.Fprintln(w, doc.Title)
+	}
+	for _, data := range doc.Tables {
+		if data.Title != "" {
+			fmt.Fprintln(w, data.Title)
+		}
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		header := table.Row{}
+		subHeader := table.Row{}
+		hasSubHeader := false
+		for _, column := range data.Columns {
+			header = append(header, column.Header)
+			subHeader = append(subHeader, column.SubHeader)
+			hasSubHeader = hasSubHeader || column.SubHeader != ""
+		}
+		if hasSubHeader {
+			t.AppendHeader(header, table.RowConfig{AutoMerge: true})
+			t.AppendHeader(subHeader)
+		} else {
+			t.AppendHeader(header)
+		}
+		t.SetColumnConfigs(data.columnConfigs())
+		if data.Painter != nil {
+			t.SetRowPainter(func(row table.Row) text.Colors {
+				return data.Painter(row)
+			})
+		}
+		for _, row := range data.sortedRows() {
+			t.AppendRow(row)
+		}
+		if data.Width > 0 {
+			t.SetAllowedRowLength(data.Width)
+		}
+		t.Render()
+	}
+	return nil
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(w io.Writer, doc Document) error {
+	if len(doc.Tables) == 0 {
+		return fmt.Errorf("markdown output is not supported")
+	}
+	if doc.Title != "" {
+		fmt.Fprintf(w, "%v\n\n", doc.Title)
+	}
+	for i, data := range doc.Tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if data.Title != "" {
+			fmt.Fprintf(w, "### %v\n\n", data.Title)
+		}
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		// Markdown only supports a single header row
+		header := table.Row{}
+		for _, column := range data.Columns {
+			header = append(header, strings.TrimSpace(column.Header+" "+column.SubHeader))
+		}
+		t.AppendHeader(header)
+		t.SetColumnConfigs(data.columnConfigs())
+		for _, row := range data.sortedRows() {
+			t.AppendRow(row)
+		}
+		t.RenderMarkdown()
+	}
+	return nil
+}
+
+type csvRenderer struct {
+	separator rune
+}
+
+func (r csvRenderer) Render(w io.Writer, doc Document) error {
+	if len(doc.Tables) == 0 {
+		return fmt.Errorf("csv output is not supported")
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = r.separator
+	for i, data := range doc.Tables {
+		// Tables are separated by an empty line
+		if i > 0 {
+			writer.Flush()
+			fmt.Fprintln(w)
+		}
+		header := make([]string, 0, len(data.Columns))
+		for _, column := range data.Columns {
+			header = append(header, column.Name)
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, row := range data.sortedRows() {
+			record := make([]string, 0, len(row))
+			for _, val := range row {
+				record = append(record, exportValue(val))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// columnConfigs returns go-pretty configs, headers may span two rows so configs are matched by position
+func (t *Table) columnConfigs() []table.ColumnConfig {
+	cc := make([]table.ColumnConfig, 0, len(t.Columns))
+	for i := range t.Columns {
+		format := t.Columns[i].Format
+		if format == nil {
+			format = formatCell
+		}
+		inverse := t.Columns[i].Inverse
+		cc = append(cc, table.ColumnConfig{
+			Number: i + 1,
+			Hidden: t.Columns[i].Hidden,
+			Transformer: func(val interface{}) string {
+				if changed, ok := val.(changedCell); ok {
+					return changed.colors(inverse).Sprint(format(changed.value))
+				}
+				return format(val)
+			},
+		})
+	}
+	return cc
+}
+
+// sortedRows returns a copy of the rows sorted by the sort columns
+func (t *Table) sortedRows() [][]interface{} {
+	rows := make([][]interface{}, len(t.Rows))
+	copy(rows, t.Rows)
+	if len(t.Sort) == 0 {
+		return rows
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, sc := range t.Sort {
+			if sc.Number <= 0 || sc.Number > len(t.Columns) {
+				continue
+			}
+			c := tools.CompareValues(unwrapCell(rows[i][sc.Number-1]), unwrapCell(rows[j][sc.Number-1]))
+			if c == 0 {
+				continue
+			}
+			if sc.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return rows
+}
+
+func formatCell(val interface{}) string {
+	return fmt.Sprintf("%v", val)
+}
+
+// RenderString renders a document to a string, mostly useful for tests
+func RenderString(format string, doc Document) (string, error) {
+	renderer, err := NewRenderer(format)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = renderer.Render(&b, doc)
+	return b.String(), err
+}
@@ -0,0 +1,92 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func testDocument() Document {
+	return Document{
+		Title: "Test",
+		Data: map[string]interface{}{
+			"name":  "zooper",
+			"items": []int{1, 2},
+		},
+		Tables: []Table{
+			{
+				Columns: []TableColumn{
+					{Name: "name", Header: "Name"},
+					{Name: "value", Header: "Value", SubHeader: "Now"},
+					{Name: "hidden", Header: "Hidden", Hidden: true},
+				},
+				Rows: [][]interface{}{
+					{"b", 10.5, "x"},
+					{"a", 2.0, "y"},
+					{"c", 100.0, "z"},
+				},
+				Sort: []SortColumn{{Number: 2, Desc: true}},
+			},
+		},
+	}
+}
+
+func TestRenderCsv(t *testing.T) {
+	out, err := RenderString("csv", testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "name,value,hidden\nc,100,z\nb,10.5,x\na,2,y\n"
+	if out != expected {
+		t.Fatalf("Unexpected csv\n%v", out)
+	}
+	out, _ = RenderString("tsv", testDocument())
+	if !strings.HasPrefix(out, "name\tvalue\thidden\n") {
+		t.Fatalf("Unexpected tsv\n%v", out)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	out, err := RenderString("markdown", testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if lines[0] != "Test" || lines[2] != "| Name | Value Now |" {
+		t.Fatalf("Unexpected markdown\n%v", out)
+	}
+	if lines[4] != "| c | 100 |" || len(lines) != 7 {
+		t.Fatalf("Unexpected markdown rows\n%v", out)
+	}
+}
+
+func TestRenderYaml(t *testing.T) {
+	out, err := RenderString("yaml", testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "items:\n  - 1\n  - 2\nname: zooper\n"
+	if out != expected {
+		t.Fatalf("Unexpected yaml\n%v", out)
+	}
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	if _, err := NewRenderer("xml"); err == nil {
+		t.Fatal("Expecting error on unsupported format")
+	}
+	if _, err := RenderString("table", Document{Data: 1}); err == nil {
+		t.Fatal("Expecting error on document without tables")
+	}
+}
+
+func TestSortedRowsMultipleKeys(t *testing.T) {
+	table := Table{
+		Columns: []TableColumn{{Name: "a"}, {Name: "b"}},
+		Rows:    [][]interface{}{{1, "x"}, {2, "y"}, {1, "z"}},
+		Sort:    []SortColumn{{Number: 1}, {Number: 2, Desc: true}},
+	}
+	rows := table.sortedRows()
+	if rows[0][1] != "z" || rows[1][1] != "x" || rows[2][1] != "y" {
+		t.Errorf("unexpected order %v", rows)
+	}
+}
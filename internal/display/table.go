@@ -2,112 +2,157 @@ package display
 
 import (
 	"fmt"
-	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/filter"
 	"github.com/zooper-corp/mooncli/internal/tools"
-	"os"
 	"strings"
 )
 
-func DumpTable(data client.CollatorPool, client *client.Client, options config.TableOptions) {
-	rowConfigAutoMerge := table.RowConfig{AutoMerge: true}
-	fmt.Printf(
-		"Chain:%v runtime:%v round: %v block:#%v\n",
-		client.Chain,
-		client.SpecVersion,
-		client.SnapRound.Number,
-		client.SnapBlock.Number,
-	)
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(
-		table.Row{
-			"Display",
-			"Rank",
-			"Selected",
-			"Counted",
-			"Blocks",
-			"Blocks",
-			"Balance",
-			"Revokes",
-			"Revokes",
-			"Revokes",
-		},
-		rowConfigAutoMerge,
-	)
-	t.AppendHeader(table.Row{
-		"",
-		"",
-		"",
-		"Free",
-		"Now",
-		"Avg",
-		"",
-		"Counted",
-		"Delta",
-		"New Rank",
-	})
-	cc := []table.ColumnConfig{
-		{
-			Name: "Display",
-			Transformer: func(val interface{}) string {
-				return strings.Trim(val.(string), " ")
-			},
-		},
-		{Name: "Rank"},
-		{Name: "Selected", Hidden: true},
-		{Name: "Counted"},
-		{Name: "Blocks"},
-		{Name: "Blocks Avg", Hidden: options.Compact},
-		{Name: "Balance", Hidden: options.Compact},
-		{Name: "New Counted", Hidden: options.Compact},
-		{Name: "New Delta", Hidden: options.Compact},
-		{Name: "New Rank", Hidden: options.Compact},
+// maxNameWidth maximum width in terminal cells of a display name
+const maxNameWidth = 32
+
+// collatorsData is the structured output of collator commands
+type collatorsData struct {
+	Client *client.Client      `json:"info"`
+	Pool   client.CollatorPool `json:"collator_pool"`
+}
+
+// CollatorsDocument returns the collator ranking, not selected candidates are greyed out and selected
+// candidates out of the active set are highlighted
+func CollatorsDocument(data client.CollatorPool, c *client.Client, options config.TableOptions) (Document, error) {
+	t, err := CollatorsTable(data, options)
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{
+		Title:  chainTitle(c),
+		Data:   collatorsData{Client: c, Pool: data},
+		Tables: []Table{t},
+	}, nil
+}
+
+// CollatorsTable returns one row per collator with the selected columns
+func CollatorsTable(data client.CollatorPool, options config.TableOptions) (Table, error) {
+	columns, err := selectColumns(options.Columns, &data)
+	if err != nil {
+		return Table{}, err
+	}
+	// Sorting on a column that is not shown adds it as hidden
+	sortColumns := make([]SortColumn, 0)
+	for _, key := range options.GetSortKeys() {
+		name, desc := filter.ParseSortKey(key)
+		sortColumn, err := findColumn(name, &data)
+		if err != nil {
+			return Table{}, err
+		}
+		sortIndex := -1
+		for i := range columns {
+			if columns[i].Name == sortColumn.Name {
+				sortIndex = i
+			}
+		}
+		if sortIndex < 0 {
+			sortColumn.Hidden = true
+			columns = append(columns, sortColumn)
+			sortIndex = len(columns) - 1
+		}
+		sortColumns = append(sortColumns, SortColumn{Number: sortIndex + 1, Desc: desc})
+	}
+	for i := range columns {
+		if len(sortColumns) == 0 && columns[i].Name == "rank" {
+			sortColumns = append(sortColumns, SortColumn{Number: i + 1})
+		}
+	}
+	result := Table{
+		Columns: make([]TableColumn, 0, len(columns)),
+		Rows:    make([][]interface{}, 0, len(data.Collators)),
+		Sort:    sortColumns,
+		Width:   options.GetTableWidth(),
+	}
+	for i := range columns {
+		result.Columns = append(result.Columns, columns[i].tableColumn(options))
+	}
+	ctx := newColumnContext(&data, options)
+	for _, info := range data.Collators {
+		row := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.Value(&info, ctx))
+		}
+		result.Rows = append(result.Rows, row)
 	}
-	t.SetColumnConfigs(cc)
-	// Add rows
-	t.SetRowPainter(func(row table.Row) text.Colors {
-		if !row[2].(bool) {
+	rankIndex := columnIndex(result.Columns, "rank")
+	selectedIndex := columnIndex(result.Columns, "selected")
+	result.Painter = func(row []interface{}) text.Colors {
+		if selectedIndex >= 0 && !unwrapCell(row[selectedIndex]).(bool) {
 			return text.Colors{text.FgHiBlack}
-		} else if row[1].(uint32) > data.SelectedSize {
+		} else if rankIndex >= 0 && unwrapCell(row[rankIndex]).(uint32) > data.SelectedSize {
 			return text.Colors{text.FgYellow}
 		}
 		return nil
-	})
-	revokeRound := data.RoundNumber + options.RevokeRounds
-	for _, info := range data.Collators {
-		t.AppendRow(table.Row{
-			tools.ToAscii(info.DisplayName()),
-			info.Rank,
-			info.Selected,
-			// Counted
-			tools.Humanize(info.Counted.Float64()),
-			// Blocks
-			fmt.Sprintf("%v", info.History[data.RoundNumber].Blocks),
-			fmt.Sprintf("%.1f", info.AverageBlocks()),
-			// Balance
-			tools.Humanize(info.Balance.GetTransferableBalance().Float64()),
-			// Revokes
-			tools.Humanize(info.RevokeAt(revokeRound).Counted.Float64()),
-			tools.Humanize(info.Counted.Float64() - info.RevokeAt(revokeRound).Counted.Float64()),
-			fmt.Sprintf("%v", info.Revokes[revokeRound].Rank),
-		})
 	}
-	// Sort and render
-	t.SetAllowedRowLength(options.GetTableWidth())
-	sortIndex := 2
-	if options.GetSortKey() != "" {
-		sk := strings.ReplaceAll(options.GetSortKey(), " ", "")
-		for i, c := range cc {
-			k := strings.ReplaceAll(c.Name, " ", "")
-			if strings.EqualFold(sk, k) {
-				sortIndex = i + 1
-				break
+	return result, nil
+}
+
+// CollatorName returns the collator name as shown in tables, verified identities are marked
+func CollatorName(info *client.CollatorInfo, options config.TableOptions) string {
+	return displayName(info, options)
+}
+
+func displayName(info *client.CollatorInfo, options config.TableOptions) string {
+	name := formatName(info.Display, info.Address, options)
+	if info.Identity.Verified {
+		if options.Ascii {
+			name = name + " (v)"
+		} else {
+			name = name + " ✓"
+		}
+	}
+	return name
+}
+
+// formatName keeps unicode names in a form whose width can be computed, in ascii mode names are
+// transliterated, in both cases the short address is used if nothing is left
+func formatName(name string, address string, options config.TableOptions) string {
+	if options.Ascii {
+		name = tools.Transliterate(name)
+	} else {
+		name = strings.TrimSpace(tools.NormalizeWidth(name))
+	}
+	if name == "" {
+		return tools.ShortAddress(address)
+	}
+	tail := "…"
+	if options.Ascii {
+		tail = "~"
+	}
+	return tools.TruncateWidth(name, maxNameWidth, tail)
+}
+
+func chainTitle(client *client.Client) string {
+	return fmt.Sprintf(
+		"Chain:%v runtime:%v round: %v block:#%v",
+		client.Chain,
+		client.SpecVersion,
+		client.SnapRound.Number,
+		client.SnapBlock.Number,
+	)
+}
+
+// getSortColumns returns the columns matching the sort keys, defaults to rank
+func getSortColumns(columns []TableColumn, options config.TableOptions) []SortColumn {
+	result := make([]SortColumn, 0)
+	for _, key := range options.GetSortKeys() {
+		name, desc := filter.ParseSortKey(key)
+		for i, c := range columns {
+			if normalizeColumnName(name) == normalizeColumnName(c.Name) {
+				result = append(result, SortColumn{Number: i + 1, Desc: desc})
 			}
 		}
 	}
-	t.SortBy([]table.SortBy{{Number: sortIndex, Mode: options.GetSortMode()}})
-	t.Render()
+	if len(result) == 0 {
+		result = append(result, SortColumn{Number: columnIndex(columns, "rank") + 1})
+	}
+	return result
 }
@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"sort"
+	"strings"
+)
+
+type collatorField func(info *client.CollatorInfo, revokeRound uint32) interface{}
+
+// collatorFields are the fields available to collator filters, derived metrics use the same names as the
+// table columns with a few aliases
+var collatorFields = map[string]collatorField{
+	"address": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Address
+	},
+	"display": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.DisplayName()
+	},
+	"verified": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Identity.Verified
+	},
+	"selected": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Selected
+	},
+	"rank": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Rank
+	},
+	"blocks": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Blocks
+	},
+	"avg_blocks": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.AverageBlocks()
+	},
+	"counted": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Counted.Float64()
+	},
+	"min_bond": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.MinBond.Float64()
+	},
+	"balance": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Balance.GetTransferableBalance().Float64()
+	},
+	"apr": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Apr
+	},
+	"delegations": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return len(info.Delegations)
+	},
+	"orbiters": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return len(info.Orbiters)
+	},
+	"revokes_counted": func(info *client.CollatorInfo, revokeRound uint32) interface{} {
+		return info.RevokeAt(revokeRound).Counted.Float64()
+	},
+	"revokes_delta": func(info *client.CollatorInfo, revokeRound uint32) interface{} {
+		return info.Counted.Float64() - info.RevokeAt(revokeRound).Counted.Float64()
+	},
+	"revokes_rank": func(info *client.CollatorInfo, revokeRound uint32) interface{} {
+		return info.RevokeAt(revokeRound).Rank
+	},
+	"ratio": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Reliability.Ratio
+	},
+	"zero_rounds": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Reliability.ZeroRounds
+	},
+	"zero_streak": func(info *client.CollatorInfo, _ uint32) interface{} {
+		return info.Reliability.CurrentStreak
+	},
+}
+
+var collatorAliases = map[string]string{
+	"blocks_avg":  "avg_blocks",
+	"new_counted": "revokes_counted",
+	"new_delta":   "revokes_delta",
+	"new_rank":    "revokes_rank",
+}
+
+// CollatorFields returns the names of the fields available to collator filters
+func CollatorFields() []string {
+	result := make([]string, 0, len(collatorFields))
+	for name := range collatorFields {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func collatorFieldByName(name string) (collatorField, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := collatorAliases[name]; ok {
+		name = alias
+	}
+	field, ok := collatorFields[name]
+	return field, ok
+}
+
+// ParseCollators parses a collator filter checking all the fields exist
+func ParseCollators(s string) (*Expression, error) {
+	expr, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range expr.Identifiers() {
+		if _, ok := collatorFieldByName(name); !ok {
+			return nil, fmt.Errorf("unknown field '%v', available fields are %v", name, CollatorFields())
+		}
+	}
+	return expr, nil
+}
+
+// CollatorVars returns the variables of a collator, revoke metrics are computed at the given round
+func CollatorVars(info *client.CollatorInfo, revokeRound uint32) Vars {
+	return func(name string) (interface{}, bool) {
+		field, ok := collatorFieldByName(name)
+		if !ok {
+			return nil, false
+		}
+		return field(info, revokeRound), true
+	}
+}
+
+// Collators returns the collators matching a filter expression, all collators for an empty expression
+func Collators(collators []client.CollatorInfo, s string, revokeRound uint32) ([]client.CollatorInfo, error) {
+	if strings.TrimSpace(s) == "" {
+		return collators, nil
+	}
+	expr, err := ParseCollators(s)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.CollatorInfo, 0)
+	for i := range collators {
+		ok, err := expr.Match(CollatorVars(&collators[i], revokeRound))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, collators[i])
+		}
+	}
+	return result, nil
+}
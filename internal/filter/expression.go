@@ -0,0 +1,404 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Vars resolves an identifier to a number, string or bool value
+type Vars func(name string) (interface{}, bool)
+
+// Expression is a parsed filter such as `selected && avg_blocks < 3 || display ~ "zooper"`
+type Expression struct {
+	root node
+	src  string
+}
+
+type node interface {
+	eval(vars Vars) (interface{}, error)
+}
+
+// Parse parses a filter expression, supported operators are || && ! == != < <= > >= ~ (contains) and !~
+func Parse(s string) (*Expression, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected '%v' at %v", p.tokens[p.pos].text, p.tokens[p.pos].pos)
+	}
+	return &Expression{root: root, src: s}, nil
+}
+
+// Match evaluates the expression, the result must be a bool
+func (e *Expression) Match(vars Vars) (bool, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter '%v' is not a condition", e.src)
+	}
+	return b, nil
+}
+
+// Identifiers returns all the identifiers used in the expression
+func (e *Expression) Identifiers() []string {
+	result := make([]string, 0)
+	var walk func(n node)
+	walk = func(n node) {
+		switch v := n.(type) {
+		case identNode:
+			result = append(result, string(v))
+		case unaryNode:
+			walk(v.operand)
+		case binaryNode:
+			walk(v.left)
+			walk(v.right)
+		}
+	}
+	walk(e.root)
+	return result
+}
+
+func (e *Expression) String() string {
+	return e.src
+}
+
+type tokenKind int
+
+const (
+	tokenOp tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "!~", "<", ">", "~", "!", "(", ")"}
+
+func tokenize(s string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			// Quoted string, backslash escapes the next char
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %v", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), pos: i})
+			i = j + 1
+		case unicode.IsDigit(r) || r == '.' || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j]), pos: i})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j]), pos: i})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected '%c' at %v", r, i)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peekOp(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOp("||"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOp("&&"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.peekOp("!"); ok {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peekOp("==", "!=", "<=", ">=", "<", ">", "~", "!~")
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(t.text, "_", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%v' at %v", t.text, t.pos)
+		}
+		return literalNode{value: f}, nil
+	case tokenString:
+		return literalNode{value: t.text}, nil
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		}
+		return identNode(strings.ToLower(t.text)), nil
+	default:
+		if t.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.peekOp(")"); !ok {
+				return nil, fmt.Errorf("missing ')' for '(' at %v", t.pos)
+			}
+			p.pos++
+			return inner, nil
+		}
+		return nil, fmt.Errorf("unexpected '%v' at %v", t.text, t.pos)
+	}
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(Vars) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode string
+
+func (n identNode) eval(vars Vars) (interface{}, error) {
+	v, ok := vars(string(n))
+	if !ok {
+		return nil, fmt.Errorf("unknown field '%v'", string(n))
+	}
+	return normalize(v), nil
+}
+
+type unaryNode struct {
+	operand node
+}
+
+func (n unaryNode) eval(vars Vars) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("'!' expects a condition, got %v", v)
+	}
+	return !b, nil
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n binaryNode) eval(vars Vars) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	// Short circuit
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'%v' expects conditions, got %v", n.op, left)
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		right, err := n.right.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'%v' expects conditions, got %v", n.op, right)
+		}
+		return rb, nil
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return compare(n.op, left, right)
+}
+
+func compare(op string, left interface{}, right interface{}) (bool, error) {
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number %v with %v", l, right)
+		}
+		switch op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		}
+	case string:
+		r := fmt.Sprintf("%v", right)
+		switch op {
+		case "==":
+			return strings.EqualFold(l, r), nil
+		case "!=":
+			return !strings.EqualFold(l, r), nil
+		case "~":
+			return strings.Contains(strings.ToLower(l), strings.ToLower(r)), nil
+		case "!~":
+			return !strings.Contains(strings.ToLower(l), strings.ToLower(r)), nil
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return false, fmt.Errorf("cannot compare bool %v with %v", l, right)
+		}
+		switch op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+	return false, fmt.Errorf("operator '%v' not supported for %v", op, left)
+}
+
+// normalize converts numbers to float64
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint32:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"testing"
+)
+
+func testVars(name string) (interface{}, bool) {
+	vars := map[string]interface{}{
+		"selected":   true,
+		"rank":       uint32(60),
+		"avg_blocks": float32(2.5),
+		"display":    "🛸 Zooper Corp 🛸",
+	}
+	v, ok := vars[name]
+	return v, ok
+}
+
+func TestExpression_Match(t *testing.T) {
+	cases := map[string]bool{
+		`selected && avg_blocks < 3`:               true,
+		`rank > 50 && avg_blocks >= 10`:            false,
+		`rank > 50 || avg_blocks >= 10`:            true,
+		`display ~ "zooper"`:                       true,
+		`display !~ 'zooper'`:                      false,
+		`!selected`:                                false,
+		`!(rank <= 50) && selected == true`:        true,
+		`(rank == 60 || rank == 61) && rank != 61`: true,
+		`rank > 1_000`:                             false,
+		`avg_blocks > -1`:                          true,
+	}
+	for s, expected := range cases {
+		expr, err := Parse(s)
+		if err != nil {
+			t.Errorf("unable to parse %v: %v", s, err)
+			continue
+		}
+		ok, err := expr.Match(testVars)
+		if err != nil || ok != expected {
+			t.Errorf("got %v %v for %v, wanted %v", ok, err, s, expected)
+		}
+	}
+}
+
+func TestExpression_Errors(t *testing.T) {
+	for _, s := range []string{`rank >`, `(rank > 1`, `display ~ "zooper`, `rank # 1`, `rank 1`} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected parse error for %v", s)
+		}
+	}
+	for _, s := range []string{`rank`, `unknown > 1`, `rank > "a"`, `display < 1 && selected`} {
+		expr, err := Parse(s)
+		if err != nil {
+			t.Errorf("unable to parse %v: %v", s, err)
+			continue
+		}
+		if _, err := expr.Match(testVars); err == nil {
+			t.Errorf("expected eval error for %v", s)
+		}
+	}
+}
+
+func TestParseCollators(t *testing.T) {
+	if _, err := ParseCollators(`selected && new_delta > 10000`); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := ParseCollators(`stake > 1`); err == nil {
+		t.Errorf("expected unknown field error")
+	}
+}
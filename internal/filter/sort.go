@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"sort"
+	"strings"
+)
+
+// ParseSortKey splits a sort key into field and order, a leading '-' sorts descending
+func ParseSortKey(key string) (string, bool) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, "-") {
+		return key[1:], true
+	}
+	return strings.TrimPrefix(key, "+"), false
+}
+
+// SortCollators sorts collators in place by filter fields, keys are applied by priority and the pool order is
+// kept for ties
+func SortCollators(collators []client.CollatorInfo, keys []string, revokeRound uint32) error {
+	fields := make([]collatorField, len(keys))
+	desc := make([]bool, len(keys))
+	for i, key := range keys {
+		name, d := ParseSortKey(key)
+		field, ok := collatorFieldByName(name)
+		if !ok {
+			return fmt.Errorf("unknown sort field '%v', available fields are %v", name, CollatorFields())
+		}
+		fields[i], desc[i] = field, d
+	}
+	sort.SliceStable(collators, func(i, j int) bool {
+		for k, field := range fields {
+			c := tools.CompareValues(field(&collators[i], revokeRound), field(&collators[j], revokeRound))
+			if c == 0 {
+				continue
+			}
+			if desc[k] {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
@@ -0,0 +1,25 @@
+package filter
+
+import (
+	"github.com/zooper-corp/mooncli/internal/client"
+	"testing"
+)
+
+func TestSortCollators(t *testing.T) {
+	collators := []client.CollatorInfo{
+		{Address: "0x01", Rank: 1, Selected: true, Blocks: 2},
+		{Address: "0x02", Rank: 2, Selected: false, Blocks: 5},
+		{Address: "0x03", Rank: 3, Selected: true, Blocks: 5},
+	}
+	if err := SortCollators(collators, []string{"-blocks", "-selected"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []string{"0x03", "0x02", "0x01"} {
+		if collators[i].Address != expected {
+			t.Errorf("expected %v at %v, got %v", expected, i, collators[i].Address)
+		}
+	}
+	if err := SortCollators(collators, []string{"stake"}, 0); err == nil {
+		t.Errorf("expected unknown field error")
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/cache"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// archiveFolder is the cache folder of the round snapshots
+const archiveFolder = "rounds"
+
+// Archive keeps the last snapshot of every round, older rounds are compacted and then removed
+type Archive struct {
+	lock   sync.Mutex
+	disk   *cache.Disk
+	config config.ArchiveConfig
+	// compacted are the rounds known to have no collator history left
+	compacted map[uint32]bool
+	readOnly  bool
+}
+
+type RoundsData struct {
+	Info   ChainInfo `json:"info"`
+	Rounds []uint32  `json:"rounds"`
+}
+
+// NewArchive opens the archive in the archive folder of the data path
+func NewArchive(dataPath string, archiveConfig config.ArchiveConfig) (*Archive, error) {
+	disk, err := cache.NewDisk(filepath.Join(dataPath, "archive"))
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{disk: disk, config: archiveConfig, compacted: make(map[uint32]bool)}, nil
+}
+
+// OpenArchive opens the archive of a data path written by another process, it is never modified
+func OpenArchive(dataPath string, archiveConfig config.ArchiveConfig) *Archive {
+	disk := cache.OpenDisk(filepath.Join(dataPath, "archive"))
+	return &Archive{disk: disk, config: archiveConfig, compacted: make(map[uint32]bool), readOnly: true}
+}
+
+// SetConfig changes retention and compaction, applied on the next store
+func (a *Archive) SetConfig(archiveConfig config.ArchiveConfig) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.config = archiveConfig
+}
+
+// Store replaces the snapshot of the data round, then compacts and prunes older rounds
+func (a *Archive) Store(data CollatorData) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.readOnly {
+		return fmt.Errorf("archive is read only")
+	}
+	round := data.Info.SnapRound.Number
+	if err := a.disk.Set(roundKey(round), data); err != nil {
+		return err
+	}
+	rounds, err := a.rounds()
+	if err != nil {
+		return err
+	}
+	for _, r := range rounds {
+		switch {
+		case r+a.config.Rounds <= round:
+			log.Printf("Removing archived round %v", r)
+			if err := a.disk.Delete(roundKey(r)); err != nil {
+				return err
+			}
+			delete(a.compacted, r)
+		case a.config.CompactAfter > 0 && r+a.config.CompactAfter <= round && !a.compacted[r]:
+			if err := a.compact(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compact drops the collator history of a round, the archive itself is the history of older rounds
+func (a *Archive) compact(round uint32) error {
+	data, ok, err := a.load(round)
+	if !ok || err != nil {
+		return err
+	}
+	history := false
+	compacted := make([]client.CollatorInfo, len(data.Collators))
+	for i, collator := range data.Collators {
+		history = history || collator.History != nil
+		collator.History = nil
+		compacted[i] = collator
+	}
+	if history {
+		data.Collators = compacted
+		if err := a.disk.Set(roundKey(round), data); err != nil {
+			return err
+		}
+	}
+	a.compacted[round] = true
+	return nil
+}
+
+// Load returns the snapshot of a round
+func (a *Archive) Load(round uint32) (CollatorData, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	data, ok, err := a.load(round)
+	if err == nil && !ok {
+		err = fmt.Errorf("round %v not archived", round)
+	}
+	return data, err
+}
+
+// load reads a round, balances are converted with the token info of the round
+func (a *Archive) load(round uint32) (CollatorData, bool, error) {
+	var data CollatorData
+	ok, err := a.disk.Get(roundKey(round), &data)
+	if !ok || err != nil {
+		return CollatorData{}, ok, err
+	}
+	client.SetTokenInfo(&data, data.Info.TokenInfo)
+	return data, true, nil
+}
+
+// Rounds returns the archived rounds, oldest first
+func (a *Archive) Rounds() ([]uint32, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.rounds()
+}
+
+func (a *Archive) rounds() ([]uint32, error) {
+	keys, err := a.disk.List(archiveFolder)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]uint32, 0, len(keys))
+	for _, key := range keys {
+		round, err := strconv.ParseUint(strings.TrimPrefix(key, archiveFolder+"/"), 10, 32)
+		if err == nil {
+			result = append(result, uint32(round))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+func roundKey(round uint32) string {
+	return fmt.Sprintf("%v/%v", archiveFolder, round)
+}
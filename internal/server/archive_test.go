@@ -0,0 +1,83 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	archive, err := NewArchive(t.TempDir(), config.ArchiveConfig{Rounds: 3, CompactAfter: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collators := testCollators(t, `[{"address": "0xaa", "rank": 1, "counted": 1000, "history": {"9": {"rank": 2}}}]`)
+	for round := uint32(10); round <= 13; round++ {
+		data := CollatorData{Info: ChainInfo{SnapRound: client.SnapRound{Number: round}}, Collators: collators}
+		if err := archive.Store(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rounds, err := archive.Rounds()
+	if err != nil || len(rounds) != 3 || rounds[0] != 11 || rounds[2] != 13 {
+		t.Fatalf("unexpected rounds %v %v", rounds, err)
+	}
+	data, err := archive.Load(13)
+	if err != nil || len(data.Collators) != 1 || data.Collators[0].History == nil || data.Collators[0].Counted.Float64() != 1000 {
+		t.Fatalf("unexpected round 13 %+v %v", data, err)
+	}
+	if data, _ = archive.Load(12); data.Collators[0].History != nil {
+		t.Errorf("expected round 12 to be compacted")
+	}
+	if _, err := archive.Load(10); err == nil {
+		t.Errorf("expected round 10 to be removed")
+	}
+	// Served through the round parameter
+	c := ChainData{archive: archive, Info: ChainInfo{SnapRound: client.SnapRound{Number: 14}}}
+	for query, expected := range map[string]int{"round=12": 200, "round=10": 404, "round=x": 400} {
+		w := httptest.NewRecorder()
+		c.HandleCollators(w, httptest.NewRequest("GET", "/collators?"+query, nil))
+		if w.Code != expected {
+			t.Errorf("expected %v for %v, got %v", expected, query, w.Code)
+		}
+	}
+	w := httptest.NewRecorder()
+	c.HandleRounds(w, httptest.NewRequest("GET", "/rounds", nil))
+	if !strings.Contains(w.Body.String(), `"rounds":[11,12,13]`) {
+		t.Errorf("unexpected rounds %v", w.Body.String())
+	}
+}
+
+func TestArchiveCompactBalances(t *testing.T) {
+	archive, err := NewArchive(t.TempDir(), config.ArchiveConfig{Rounds: 10, CompactAfter: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := client.TokenInfo{TokenDecimals: 18, TokenSymbol: "GLMR"}
+	var collators []client.CollatorInfo
+	js := `[{"address": "0xaa", "counted": 1000.25, "history": {"9": {"counted": 999.5}}}]`
+	if err := client.UnmarshalJSON([]byte(js), &collators, token); err != nil {
+		t.Fatal(err)
+	}
+	// Rounds 11 and 12 were missed while the server was down
+	for _, round := range []uint32{10, 13, 14} {
+		data := CollatorData{Info: ChainInfo{SnapRound: client.SnapRound{Number: round}, TokenInfo: token}, Collators: collators}
+		if err := archive.Store(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := archive.Load(10)
+	if err != nil || data.Collators[0].History != nil {
+		t.Fatalf("expected round 10 to be compacted %+v %v", data, err)
+	}
+	// Balances keep their decimals, a missing token info would truncate them to whole tokens
+	if counted := data.Collators[0].Counted.Float64(); math.Abs(counted-1000.25) > 1e-9 {
+		t.Errorf("unexpected compacted balance %v", counted)
+	}
+	if data, _ = archive.Load(13); data.Collators[0].History == nil {
+		t.Errorf("expected round 13 to keep its history")
+	}
+}
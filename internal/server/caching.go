@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// staleIfError is how long clients may use a cached response when the server fails
+const staleIfError = 600
+
+// ETag returns a weak validator for the data version, it changes with every update
+func (i *ChainInfo) ETag() string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%v/%v/%v", i.Update.TsSecs, i.SnapBlock.Number, i.SnapBlock.Hash.Hex())
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// LastModified returns the time of the update that produced the data
+func (i *ChainInfo) LastModified() time.Time {
+	return time.Unix(int64(i.Update.TsSecs), 0).UTC()
+}
+
+// maxAge returns the seconds until the next scheduled update, zero when the update is due
+func (c *ChainData) maxAge(info *ChainInfo, now time.Time) int {
+	interval := c.getUpdateInterval()
+	if interval <= 0 || info.Update.TsSecs == 0 {
+		return 0
+	}
+	next := info.LastModified().Add(interval)
+	return int(math.Max(0, next.Sub(now).Seconds()))
+}
+
+// setCacheHeaders sets the validators and caching policy, returns true if the client copy is still valid
+func (c *ChainData) setCacheHeaders(w http.ResponseWriter, r *http.Request, info *ChainInfo) bool {
+	etag := info.ETag()
+	w.Header().Set("ETag", etag)
+	if info.Update.TsSecs != 0 {
+		w.Header().Set("Last-Modified", info.LastModified().Format(http.TimeFormat))
+	}
+	w.Header().Set(
+		"Cache-Control",
+		fmt.Sprintf("max-age=%v, stale-if-error=%v", c.maxAge(info, time.Now()), staleIfError),
+	)
+	// If-None-Match takes precedence over If-Modified-Since
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, etag)
+	}
+	if since := r.Header.Get("If-Modified-Since"); since != "" && info.Update.TsSecs != 0 {
+		t, err := http.ParseTime(since)
+		return err == nil && !info.LastModified().After(t)
+	}
+	return false
+}
+
+// etagMatches does a weak comparison of the tags listed in If-None-Match
+func etagMatches(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
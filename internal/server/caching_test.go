@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Truncate(time.Second)
+	c := ChainData{
+		updateInterval: 15 * time.Minute,
+		Info:           ChainInfo{Chain: "moonbeam", Update: ChainUpdate{TsSecs: float64(now.Unix())}},
+	}
+	get := func(header string, value string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/info", nil)
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		c.HandleInfo(w, r)
+		return w
+	}
+	w := get("", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != 200 || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expected full response with etag, got %v %v", w.Code, etag)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "max-age=840, stale-if-error=600" && cc != "max-age=839, stale-if-error=600" {
+		t.Errorf("unexpected cache control %v", cc)
+	}
+	if w = get("If-None-Match", `"other", `+etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected 304 for matching etag, got %v", w.Code)
+	}
+	if w = get("If-Modified-Since", now.Format(http.TimeFormat)); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for unmodified data, got %v", w.Code)
+	}
+	if w = get("If-Modified-Since", now.Add(-time.Second).Format(http.TimeFormat)); w.Code != 200 {
+		t.Errorf("expected 200 for modified data, got %v", w.Code)
+	}
+	// A new update changes the validator
+	c.Info.Update.TsSecs += 900
+	if w = get("If-None-Match", etag); w.Code != 200 || w.Header().Get("ETag") == etag {
+		t.Errorf("expected new etag after update, got %v", w.Code)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	c := ChainData{updateInterval: 10 * time.Minute}
+	info := ChainInfo{Update: ChainUpdate{TsSecs: 1000}}
+	if age := c.maxAge(&info, time.Unix(1000+420, 0)); age != 180 {
+		t.Errorf("expected 180 got %v", age)
+	}
+	if age := c.maxAge(&info, time.Unix(1000+900, 0)); age != 0 {
+		t.Errorf("expected 0 for a late update got %v", age)
+	}
+}
@@ -6,22 +6,34 @@ import (
 	"github.com/OrlovEvgeny/go-mcache"
 	"github.com/zooper-corp/mooncli/config"
 	"github.com/zooper-corp/mooncli/internal/client"
-	"io/ioutil"
 	"log"
-	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type ChainData struct {
-	cache          *mcache.CacheDriver
-	dataLock       sync.RWMutex
-	updateLock     sync.Mutex
-	chainConfig    config.ChainConfig
-	maxUpdateDelta time.Duration
-	Info           ChainInfo             `json:"info"`
-	Collators      []client.CollatorInfo `json:"collators"`
+	// updateErrors and followSyncNanos are first to keep 64-bit alignment for atomic access
+	updateErrors    uint64
+	followSyncNanos int64
+	followPath      string
+	network         string
+	readOnly        bool
+	started         time.Time
+	cache           *mcache.CacheDriver
+	rpcStats        *client.RpcStats
+	dataLock        sync.RWMutex
+	updateLock      sync.Mutex
+	configLock      sync.RWMutex
+	chainConfig     config.ChainConfig
+	updateInterval  time.Duration
+	maxUpdateDelta  time.Duration
+	reconfigured    chan struct{}
+	events          *eventBroker
+	archive         *Archive
+	Info            ChainInfo             `json:"info"`
+	Collators       []client.CollatorInfo `json:"collators"`
 }
 
 type CollatorData struct {
@@ -59,78 +71,103 @@ type ChainUpdate struct {
 	LenSecs float32 `json:"len"`
 }
 
-func NewChainData(chainConfig config.ChainConfig, maxUpdateDelta time.Duration) (ChainData, error) {
+func NewChainData(chainConfig config.ChainConfig, updateInterval time.Duration, maxUpdateDelta time.Duration) (ChainData, error) {
 	return ChainData{
 		cache:          mcache.New(),
+		rpcStats:       client.NewRpcStats(),
 		dataLock:       sync.RWMutex{},
 		chainConfig:    chainConfig,
+		updateInterval: updateInterval,
 		maxUpdateDelta: maxUpdateDelta,
+		events:         newEventBroker(),
+		started:        time.Now(),
+		reconfigured:   make(chan struct{}, 1),
 	}, nil
 }
 
 func (c *ChainData) UpdateFromJson(jsonPath string) error {
 	if c.updateLock.TryLock() {
 		defer c.updateLock.Unlock()
+		files, version, err := readDataFiles(jsonPath)
+		if err != nil {
+			log.Printf("Unable to read data cache: %v", err)
+			return err
+		}
 		// Load info
 		var chainInfo ChainInfo
-		err := c.readJson(fmt.Sprintf("%s/info.json", jsonPath), &chainInfo)
-		if err != nil {
+		if err := json.Unmarshal(files[infoFile], &chainInfo); err != nil {
 			log.Printf("Unable to read info from cache: %v", err)
 			return err
 		}
-		// Load collators
-		client.InitUnmarshalData(chainInfo.TokenInfo)
+		// Load collators, balances depend on the chain token
 		var collatorPool []client.CollatorInfo
-		err = c.readJson(fmt.Sprintf("%s/collators.json", jsonPath), &collatorPool)
-		if err != nil {
+		if err := client.UnmarshalJSON(files[collatorsFile], &collatorPool, chainInfo.TokenInfo); err != nil {
 			log.Printf("Unable to read collators from cache %v", err)
 			return err
 		}
+		// Load derived data
+		var derived derivedData
+		if err := client.UnmarshalJSON(files[derivedFile], &derived, chainInfo.TokenInfo); err != nil {
+			log.Printf("Unable to read derived data from cache %v", err)
+			return err
+		}
+		for i := range collatorPool {
+			collatorPool[i].Delegations = derived.Delegations[strings.ToLower(collatorPool[i].Address)]
+		}
 		// Done update backend
 		c.dataLock.Lock()
-		defer c.dataLock.Unlock()
+		prevInfo, prevCollators := c.Info, c.Collators
 		c.Info = chainInfo
 		c.Collators = collatorPool
+		c.publishUpdate(prevInfo, prevCollators)
+		c.dataLock.Unlock()
 		// Finished
 		log.Printf("Chain data loaded from JSON: %v", time.UnixMilli(int64(chainInfo.Update.TsSecs*1000)))
+		if version < cacheVersion && !c.readOnly {
+			log.Printf("Migrating data cache from version %v to %v", version, cacheVersion)
+			return c.StoreToJson(jsonPath)
+		}
 		return nil
 	}
 	return fmt.Errorf("unable to get data lock")
 }
 
-func (c *ChainData) readJson(jsonPath string, target interface{}) error {
-	jsonFile, err := os.Open(jsonPath)
-	if err != nil {
-		log.Printf("Unable to load JSON from %v: %v", jsonPath, err)
-		return err
+// StoreToJson writes the data files and then the manifest listing them, a crash before the manifest is replaced
+// leaves checksums that do not match and the cache is not loaded
+func (c *ChainData) StoreToJson(jsonPath string) error {
+	if c.readOnly {
+		return fmt.Errorf("data is read only when following %v", c.followPath)
 	}
-	defer func(jsonFile *os.File) {
-		_ = jsonFile.Close()
-	}(jsonFile)
-	byteValue, err := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		log.Printf("Unable to read JSON from %v: %v", jsonPath, err)
-		return err
+	data := c.GetCollators()
+	derived := derivedData{
+		Version:     cacheVersion,
+		Delegations: make(map[string][]client.DelegatorState),
 	}
-	err = json.Unmarshal(byteValue, target)
-	return err
-}
-
-func (c *ChainData) StoreToJson(jsonPath string) error {
-	file, err := json.MarshalIndent(c.Info, "", " ")
-	err = ioutil.WriteFile(fmt.Sprintf("%s/info.json", jsonPath), file, 0644)
-	if err != nil {
-		log.Printf("Unable to write JSON to %v: %v", jsonPath, err)
-		return err
+	for _, collator := range data.Collators {
+		derived.Delegations[strings.ToLower(collator.Address)] = collator.Delegations
 	}
-	file, err = json.MarshalIndent(c.Collators, "", " ")
-	err = ioutil.WriteFile(fmt.Sprintf("%s/collators.json", jsonPath), file, 0644)
+	err := writeDataFiles(jsonPath, map[string]interface{}{
+		infoFile:      data.Info,
+		collatorsFile: data.Collators,
+		derivedFile:   derived,
+	})
 	if err != nil {
 		log.Printf("Unable to write JSON to %v: %v", jsonPath, err)
 		return err
 	}
 	log.Printf("Data stored to JSON cache")
-	return err
+	if c.archive != nil {
+		if err := c.archive.Store(data); err != nil {
+			log.Printf("Unable to archive round %v: %v", data.Info.SnapRound.Number, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// SetArchive enables the per round archive, rounds are archived when data is stored
+func (c *ChainData) SetArchive(archive *Archive) {
+	c.archive = archive
 }
 
 func (c *ChainData) Update(historyRounds uint32) error {
@@ -139,10 +176,10 @@ func (c *ChainData) Update(historyRounds uint32) error {
 		start := time.Now().UnixMilli()
 		// Create basic client
 		log.Printf("Starting update")
-		chainClient, err := client.NewClientWithExternalCache(c.chainConfig, c.cache)
+		chainClient, err := client.NewClientWithExternalCache(c.getChainConfig(), c.cache, c.rpcStats)
 		if err != nil {
 			log.Printf("Unable to create client %v", err)
-			return err
+			return c.updateFailed(err)
 		}
 		// Fetch collator pool
 		log.Printf("Fetching collator pool history:%v revokes:%v\n", historyRounds, true)
@@ -152,7 +189,7 @@ func (c *ChainData) Update(historyRounds uint32) error {
 		})
 		if err != nil {
 			log.Printf("Unable to fetch collator pool %v", err)
-			return err
+			return c.updateFailed(err)
 		}
 		// Check pool size
 		if len(collatorPool.Collators) != int(chainClient.SnapStaking.Total) {
@@ -161,12 +198,13 @@ func (c *ChainData) Update(historyRounds uint32) error {
 				len(collatorPool.Collators),
 				int(chainClient.SnapStaking.Total),
 			)
-			return fmt.Errorf("pool size does not match")
+			return c.updateFailed(fmt.Errorf("pool size does not match"))
 		}
 		// Done update backend
 		c.dataLock.Lock()
 		defer c.dataLock.Unlock()
 		updateTime := uint32(time.Now().UnixMilli() - start)
+		prevInfo, prevCollators := c.Info, c.Collators
 		c.Info = ChainInfo{
 			Server: "MoonCli by 🛸 Zooper Corp 🛸",
 			Update: ChainUpdate{
@@ -181,6 +219,7 @@ func (c *ChainData) Update(historyRounds uint32) error {
 			TokenInfo:   chainClient.TokenInfo,
 		}
 		c.Collators = collatorPool.Collators
+		c.publishUpdate(prevInfo, prevCollators)
 		// Finished
 		log.Printf("Chain update done in %vs", float64(updateTime*100)/100000.0)
 		return nil
@@ -190,6 +229,48 @@ func (c *ChainData) Update(historyRounds uint32) error {
 	}
 }
 
+// Reconfigure changes the endpoints and update interval, the update loop is rescheduled
+func (c *ChainData) Reconfigure(chainConfig config.ChainConfig, updateInterval time.Duration, maxUpdateDelta time.Duration) {
+	c.configLock.Lock()
+	c.chainConfig = chainConfig
+	c.updateInterval = updateInterval
+	c.maxUpdateDelta = maxUpdateDelta
+	c.configLock.Unlock()
+	select {
+	case c.reconfigured <- struct{}{}:
+	default:
+	}
+}
+
+func (c *ChainData) getChainConfig() config.ChainConfig {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return c.chainConfig
+}
+
+func (c *ChainData) getUpdateInterval() time.Duration {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return c.updateInterval
+}
+
+func (c *ChainData) getMaxUpdateDelta() time.Duration {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return c.maxUpdateDelta
+}
+
+// updateFailed counts a failed update
+func (c *ChainData) updateFailed(err error) error {
+	atomic.AddUint64(&c.updateErrors, 1)
+	return err
+}
+
+// UpdateErrors returns the number of failed updates since start
+func (c *ChainData) UpdateErrors() uint64 {
+	return atomic.LoadUint64(&c.updateErrors)
+}
+
 func (c *ChainData) GetInfo() *ChainInfo {
 	c.dataLock.RLock()
 	defer c.dataLock.RUnlock()
@@ -232,6 +313,19 @@ func (c *ChainData) GetDelegations(address string) DelegationData {
 	}
 }
 
+// selectedCollators returns the lower case addresses of the selected collators
+func (c *ChainData) selectedCollators() map[string]bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	result := make(map[string]bool)
+	for _, collator := range c.Collators {
+		if collator.Selected {
+			result[strings.ToLower(collator.Address)] = true
+		}
+	}
+	return result
+}
+
 func (c *ChainData) GetCollator(address string) CollatorData {
 	c.dataLock.RLock()
 	defer c.dataLock.RUnlock()
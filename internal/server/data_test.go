@@ -0,0 +1,106 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJsonCache(t *testing.T) {
+	path := t.TempDir()
+	collators := testCollators(t, `[{"address": "0xAA", "rank": 1, "counted": 3000}, {"address": "0xbb", "rank": 2}]`)
+	var delegations []client.DelegatorState
+	testJson(t, `[{"address": "0x01", "amount": 500, "revoke_amount": 500, "revoke_round": 12}]`, &delegations)
+	collators[0].Delegations = delegations
+	stored := ChainData{
+		Info:      ChainInfo{Chain: "moonbeam", TokenInfo: client.TokenInfo{TokenDecimals: 18, TokenSymbol: "GLMR"}},
+		Collators: collators,
+	}
+	if err := stored.StoreToJson(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := NewChainData(config.ChainConfig{}, 0, 0)
+	if err := loaded.UpdateFromJson(path); err != nil {
+		t.Fatal(err)
+	}
+	result := loaded.GetDelegations("0x01").Delegations
+	if len(result) != 1 || result[0].Collator != "0xAA" || math.Abs(result[0].Amount.Float64()-500) > 1e-9 || result[0].RevokeRound != 12 {
+		t.Errorf("unexpected delegations %+v", result)
+	}
+	// A partial write does not match the manifest
+	collatorsJson, _ := os.ReadFile(filepath.Join(path, collatorsFile))
+	if err := os.WriteFile(filepath.Join(path, collatorsFile), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.UpdateFromJson(path); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+	// Version 1 caches have no manifest and are migrated on load
+	_ = os.WriteFile(filepath.Join(path, collatorsFile), collatorsJson, 0644)
+	_ = os.Remove(filepath.Join(path, manifestFile))
+	_ = os.WriteFile(filepath.Join(path, derivedFile), []byte(`{"version": 1, "delegations": {}}`), 0644)
+	if err := loaded.UpdateFromJson(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, version, err := readDataFiles(path); err != nil || version != cacheVersion {
+		t.Errorf("expected migrated cache, got version %v %v", version, err)
+	}
+}
+
+func TestJsonCacheVersion0(t *testing.T) {
+	// Data path as written before delegations were cached
+	path := t.TempDir()
+	info := `{
+ "server": "moonbeam",
+ "update": {
+  "ts": 1650000000,
+  "len": 12.5
+ },
+ "chain": "moonbeam",
+ "spec": 1300,
+ "token": {
+  "decimals": 18,
+  "symbol": "GLMR"
+ }
+}`
+	collators := `[
+ {
+  "address": "0xAA",
+  "selected": true,
+  "rank": 1,
+  "blocks": 10,
+  "counted": 3000.5,
+  "min_bond": 500,
+  "balance": {
+   "free": 100,
+   "reserved": 2000
+  },
+  "display": "alpha"
+ }
+]`
+	_ = os.WriteFile(filepath.Join(path, infoFile), []byte(info), 0644)
+	_ = os.WriteFile(filepath.Join(path, collatorsFile), []byte(collators), 0644)
+	loaded, _ := NewChainData(config.ChainConfig{}, 0, 0)
+	if err := loaded.UpdateFromJson(path); err != nil {
+		t.Fatal(err)
+	}
+	data := loaded.GetCollators()
+	if len(data.Collators) != 1 || data.Collators[0].Display != "alpha" || math.Abs(data.Collators[0].Counted.Float64()-3000.5) > 1e-9 {
+		t.Errorf("unexpected collators %+v", data.Collators)
+	}
+	if len(loaded.GetDelegations("0x01").Delegations) != 0 {
+		t.Errorf("expected no delegations for a version 0 cache")
+	}
+	// Rewritten with a manifest and empty derived data
+	files, version, err := readDataFiles(path)
+	if err != nil || version != cacheVersion {
+		t.Fatalf("expected migrated cache, got version %v %v", version, err)
+	}
+	if !strings.Contains(string(files[derivedFile]), `"version": 2`) {
+		t.Errorf("unexpected derived data %s", files[derivedFile])
+	}
+}
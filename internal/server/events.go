@@ -0,0 +1,248 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventBuffer is the number of events queued for a slow subscriber before it starts missing them
+const eventBuffer = 16
+
+// eventKeepAlive is the interval of the comment lines keeping idle streams open through proxies
+const eventKeepAlive = 30 * time.Second
+
+// Event is pushed to /events subscribers, Type is one of info, update or round
+type Event struct {
+	Id   uint64
+	Type string
+	Data interface{}
+}
+
+// UpdateEvent is published after every successful chain update
+type UpdateEvent struct {
+	Info    ChainInfo        `json:"info"`
+	Changes []CollatorChange `json:"changes"`
+}
+
+// RoundEvent is published when an update lands in a new round
+type RoundEvent struct {
+	From uint32           `json:"from"`
+	To   client.SnapRound `json:"to"`
+}
+
+// CollatorChange is the compact state of a collator that joined, left or changed rank, counted, selection or blocks
+type CollatorChange struct {
+	Address  string              `json:"address"`
+	Display  string              `json:"display,omitempty"`
+	Change   string              `json:"change"`
+	Rank     uint32              `json:"rank,omitempty"`
+	PrevRank uint32              `json:"prev_rank,omitempty"`
+	Counted  client.TokenBalance `json:"counted"`
+	Selected bool                `json:"selected"`
+	Blocks   uint32              `json:"blocks"`
+}
+
+type subscriber struct {
+	events    chan Event
+	addresses map[string]bool
+}
+
+// eventBroker fans out events to subscribers, a full subscriber queue drops the event for that subscriber only
+type eventBroker struct {
+	lock        sync.Mutex
+	lastId      uint64
+	subscribers map[*subscriber]bool
+	// done is closed to end all streams
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: make(map[*subscriber]bool), done: make(chan struct{})}
+}
+
+// close ends the streams of all subscribers
+func (b *eventBroker) close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+}
+
+// subscribe registers a subscriber, an empty address list receives changes of all collators
+func (b *eventBroker) subscribe(addresses []string) *subscriber {
+	s := &subscriber{events: make(chan Event, eventBuffer)}
+	if len(addresses) > 0 {
+		s.addresses = make(map[string]bool)
+		for _, address := range addresses {
+			s.addresses[strings.ToLower(address)] = true
+		}
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[s] = true
+	return s
+}
+
+func (b *eventBroker) unsubscribe(s *subscriber) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.subscribers, s)
+}
+
+func (b *eventBroker) publish(eventType string, data interface{}) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.lastId++
+	for s := range b.subscribers {
+		event, ok := s.filter(Event{Id: b.lastId, Type: eventType, Data: data})
+		if !ok {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			log.Printf("Event subscriber queue full, dropping %v event %v", eventType, b.lastId)
+		}
+	}
+}
+
+// filter restricts update events to the subscribed collators, updates without any of them are skipped
+func (s *subscriber) filter(event Event) (Event, bool) {
+	update, ok := event.Data.(UpdateEvent)
+	if !ok || s.addresses == nil {
+		return event, true
+	}
+	changes := make([]CollatorChange, 0)
+	for _, change := range update.Changes {
+		if s.addresses[strings.ToLower(change.Address)] {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return event, false
+	}
+	event.Data = UpdateEvent{Info: update.Info, Changes: changes}
+	return event, true
+}
+
+// collatorChanges compares two collator pools and returns the collators that changed
+func collatorChanges(from []client.CollatorInfo, to []client.CollatorInfo) []CollatorChange {
+	previous := make(map[string]*client.CollatorInfo)
+	for i := range from {
+		previous[strings.ToLower(from[i].Address)] = &from[i]
+	}
+	changes := make([]CollatorChange, 0)
+	for i := range to {
+		collator := &to[i]
+		change := CollatorChange{
+			Address:  collator.Address,
+			Display:  collator.Display,
+			Change:   "updated",
+			Rank:     collator.Rank,
+			Counted:  collator.Counted,
+			Selected: collator.Selected,
+			Blocks:   collator.Blocks,
+		}
+		key := strings.ToLower(collator.Address)
+		if prev, ok := previous[key]; !ok {
+			change.Change = "joined"
+		} else {
+			delete(previous, key)
+			change.PrevRank = prev.Rank
+			if prev.Rank == collator.Rank &&
+				prev.Selected == collator.Selected &&
+				prev.Blocks == collator.Blocks &&
+				prev.Counted.Float64() == collator.Counted.Float64() {
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+	// Leftovers are no longer in the pool
+	for i := range from {
+		if prev, ok := previous[strings.ToLower(from[i].Address)]; ok {
+			changes = append(changes, CollatorChange{
+				Address:  prev.Address,
+				Display:  prev.Display,
+				Change:   "left",
+				PrevRank: prev.Rank,
+			})
+		}
+	}
+	return changes
+}
+
+// publishUpdate pushes the update event and, when the round moved, a round event
+func (c *ChainData) publishUpdate(prevInfo ChainInfo, prevCollators []client.CollatorInfo) {
+	if c.events == nil {
+		return
+	}
+	if prevInfo.SnapRound.Number != 0 && prevInfo.SnapRound.Number != c.Info.SnapRound.Number {
+		c.events.publish("round", RoundEvent{From: prevInfo.SnapRound.Number, To: c.Info.SnapRound})
+	}
+	c.events.publish("update", UpdateEvent{Info: c.Info, Changes: collatorChanges(prevCollators, c.Collators)})
+}
+
+// HandleEvents streams update and round events as server-sent events, the optional address parameter is a
+// comma separated list of collators to receive changes for
+func (c *ChainData) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok || c.events == nil {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	var addresses []string
+	if param := r.URL.Query().Get("address"); param != "" {
+		addresses = strings.Split(param, ",")
+	}
+	s := c.events.subscribe(addresses)
+	defer c.events.unsubscribe(s)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Current state first so clients do not wait for the next update
+	if err := writeEvent(w, Event{Type: "info", Data: c.GetInfo()}); err != nil {
+		return
+	}
+	flusher.Flush()
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event := <-s.events:
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-c.events.done:
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event Event) error {
+	js, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if event.Id != 0 {
+		if _, err = fmt.Fprintf(w, "id: %v\n", event.Id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %v\ndata: %s\n\n", event.Type, js)
+	return err
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testJson(t *testing.T, js string, target interface{}) {
+	if err := client.UnmarshalJSON([]byte(js), target, client.TokenInfo{TokenDecimals: 18, TokenSymbol: "GLMR"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testCollators(t *testing.T, js string) []client.CollatorInfo {
+	var collators []client.CollatorInfo
+	testJson(t, js, &collators)
+	return collators
+}
+
+func TestCollatorChanges(t *testing.T) {
+	from := testCollators(t, `[
+		{"address": "0xaa", "rank": 1, "counted": 3000, "selected": true},
+		{"address": "0xbb", "rank": 2, "counted": 2000, "selected": true},
+		{"address": "0xcc", "rank": 3, "counted": 1000}
+	]`)
+	to := testCollators(t, `[
+		{"address": "0xAA", "rank": 1, "counted": 3000, "selected": true},
+		{"address": "0xbb", "rank": 3, "counted": 2000, "selected": true, "blocks": 1},
+		{"address": "0xdd", "rank": 2, "counted": 2500, "selected": true}
+	]`)
+	changes := collatorChanges(from, to)
+	got := make(map[string]CollatorChange)
+	for _, change := range changes {
+		got[change.Address] = change
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %v", changes)
+	}
+	if c := got["0xbb"]; c.Change != "updated" || c.PrevRank != 2 || c.Rank != 3 || c.Blocks != 1 {
+		t.Errorf("unexpected update %+v", c)
+	}
+	if c := got["0xdd"]; c.Change != "joined" {
+		t.Errorf("unexpected join %+v", c)
+	}
+	if c := got["0xcc"]; c.Change != "left" || c.PrevRank != 3 {
+		t.Errorf("unexpected leave %+v", c)
+	}
+}
+
+func TestHandleEvents(t *testing.T) {
+	c := ChainData{
+		events:    newEventBroker(),
+		Info:      ChainInfo{Chain: "moonbeam", SnapRound: client.SnapRound{Number: 10}},
+		Collators: testCollators(t, `[{"address": "0xaa", "rank": 1}, {"address": "0xbb", "rank": 2}]`),
+	}
+	server := httptest.NewServer(http.HandlerFunc(c.HandleEvents))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "?address=0xBB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %v", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, string) {
+		var eventType, data string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && eventType != "":
+				return eventType, data
+			case strings.HasPrefix(line, "event: "):
+				eventType = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+	if eventType, _ := readEvent(); eventType != "info" {
+		t.Fatalf("expected info event first, got %v", eventType)
+	}
+	// Only 0xaa changed, the subscriber is not notified
+	prevInfo, prevCollators := c.Info, c.Collators
+	c.Collators = testCollators(t, `[{"address": "0xaa", "rank": 1, "blocks": 1}, {"address": "0xbb", "rank": 2}]`)
+	c.publishUpdate(prevInfo, prevCollators)
+	// Round change and 0xbb changed
+	prevInfo, prevCollators = c.Info, c.Collators
+	c.Info.SnapRound.Number = 11
+	c.Collators = testCollators(t, `[{"address": "0xaa", "rank": 1, "blocks": 1}, {"address": "0xbb", "rank": 2, "blocks": 1}]`)
+	c.publishUpdate(prevInfo, prevCollators)
+	if eventType, data := readEvent(); eventType != "round" || !strings.Contains(data, `"from":10`) {
+		t.Fatalf("expected round event, got %v %v", eventType, data)
+	}
+	eventType, data := readEvent()
+	var update UpdateEvent
+	if err := json.Unmarshal([]byte(data), &update); err != nil || eventType != "update" {
+		t.Fatalf("expected update event, got %v %v", eventType, data)
+	}
+	if len(update.Changes) != 1 || update.Changes[0].Address != "0xbb" {
+		t.Errorf("expected only 0xbb changes, got %+v", update.Changes)
+	}
+}
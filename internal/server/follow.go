@@ -0,0 +1,61 @@
+package server
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Follow loads the data of a writer instance and then reloads it in background whenever its manifest changes,
+// until quit is closed, a following instance never writes, the returned channel is closed when reloads are over
+func (c *ChainData) Follow(path string, interval time.Duration, quit chan struct{}) <-chan struct{} {
+	c.followPath = path
+	c.readOnly = true
+	loaded := ""
+	sync := func() {
+		b, err := ioutil.ReadFile(filepath.Join(path, manifestFile))
+		if err != nil {
+			log.Printf("Unable to read followed manifest: %v", err)
+			return
+		}
+		// A manifest replaced while loading is loaded again on the next check
+		if sum := checksum(b); sum != loaded {
+			if err := c.UpdateFromJson(path); err != nil {
+				log.Printf("Unable to reload followed data: %v", err)
+				return
+			}
+			loaded = sum
+		}
+		atomic.StoreInt64(&c.followSyncNanos, time.Now().UnixNano())
+	}
+	sync()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sync()
+			case <-quit:
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// FollowLag returns the time since the followed data was last found in sync, zero when not following
+func (c *ChainData) FollowLag() time.Duration {
+	if c.followPath == "" {
+		return 0
+	}
+	synced := atomic.LoadInt64(&c.followSyncNanos)
+	if synced == 0 {
+		return time.Since(c.started)
+	}
+	return time.Since(time.Unix(0, synced))
+}
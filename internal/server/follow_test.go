@@ -0,0 +1,93 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	path := t.TempDir()
+	writer := ChainData{
+		Info:      ChainInfo{Chain: "moonbeam", SnapRound: client.SnapRound{Number: 10}},
+		Collators: testCollators(t, `[{"address": "0xaa", "rank": 1}]`),
+	}
+	if err := writer.StoreToJson(path); err != nil {
+		t.Fatal(err)
+	}
+	follower, _ := NewChainData(config.ChainConfig{}, time.Minute, time.Hour)
+	quit := make(chan struct{})
+	defer close(quit)
+	follower.Follow(path, 10*time.Millisecond, quit)
+	if info := follower.GetInfo(); info.SnapRound.Number != 10 {
+		t.Fatalf("expected followed round 10, got %v", info.SnapRound.Number)
+	}
+	writer.Info.SnapRound.Number = 11
+	writer.Info.Update.TsSecs = float64(time.Now().Unix())
+	if err := writer.StoreToJson(path); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for follower.GetInfo().SnapRound.Number != 11 {
+		if time.Now().After(deadline) {
+			t.Fatalf("follower did not reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := follower.StoreToJson(path); err == nil {
+		t.Errorf("expected follower to be read only")
+	}
+	w := httptest.NewRecorder()
+	follower.HandleHealth(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "follower lag 0") {
+		t.Errorf("unexpected health %v %v", w.Code, w.Body.String())
+	}
+}
+
+func TestFollowArchive(t *testing.T) {
+	path := t.TempDir()
+	httpConfig := config.HttpConfig{
+		Networks:       []config.NetworkConfig{{Name: "moonbeam"}},
+		FollowPath:     path,
+		FollowInterval: time.Minute,
+		Archive:        config.ArchiveConfig{Rounds: 84},
+	}
+	quit := make(chan struct{})
+	network, err := StartNetwork(httpConfig, httpConfig.Networks[0], quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(quit)
+	<-network.done
+	if _, err := os.Stat(filepath.Join(path, "archive")); !os.IsNotExist(err) {
+		t.Errorf("expected follower to leave the followed data path unchanged, got %v", err)
+	}
+	if rounds, err := network.Data.archive.Rounds(); err != nil || len(rounds) != 0 {
+		t.Errorf("unexpected rounds %v %v", rounds, err)
+	}
+	if err := network.Data.archive.Store(CollatorData{}); err == nil {
+		t.Errorf("expected follower archive to be read only")
+	}
+}
+
+func TestLockDataPath(t *testing.T) {
+	path := t.TempDir()
+	lock, err := LockDataPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LockDataPath(path); err == nil {
+		t.Errorf("expected second writer to be refused")
+	}
+	_ = lock.Close()
+	lock, err = LockDataPath(path)
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	_ = lock.Close()
+}
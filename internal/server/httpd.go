@@ -1,46 +1,130 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/NYTimes/gziphandler"
 	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
+// startRetryDelay is the wait between failed first updates of a network
+const startRetryDelay = 10 * time.Second
+
 func (c *ChainData) HandleInfo(w http.ResponseWriter, r *http.Request) {
+	data, status, err := c.collatorsAt(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	c.handleJsonResponse(w, r, &data.Info, data.Info)
+}
+
+func (c *ChainData) HandleRounds(w http.ResponseWriter, r *http.Request) {
+	if c.archive == nil {
+		http.Error(w, "Round archive is disabled", 404)
+		return
+	}
+	rounds, err := c.archive.Rounds()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	info := c.GetInfo()
-	handleJsonResponse(w, info)
+	c.handleJsonResponse(w, r, info, RoundsData{Info: *info, Rounds: rounds})
+}
+
+// collatorsAt returns the current data or the archived data of the round parameter with the error status
+func (c *ChainData) collatorsAt(r *http.Request) (CollatorData, int, error) {
+	param := r.URL.Query().Get("round")
+	if param == "" {
+		return c.GetCollators(), 200, nil
+	}
+	round, err := strconv.ParseUint(param, 10, 32)
+	if err != nil {
+		return CollatorData{}, 400, fmt.Errorf("invalid round '%v'", param)
+	}
+	if c.archive == nil {
+		return CollatorData{}, 404, fmt.Errorf("round archive is disabled")
+	}
+	data, err := c.archive.Load(uint32(round))
+	if err != nil {
+		return CollatorData{}, 404, err
+	}
+	return data, 200, nil
 }
 
 func (c *ChainData) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	info := c.GetInfo()
+	maxDelta := c.getMaxUpdateDelta().Seconds()
 	update := time.Unix(int64(info.Update.TsSecs), 0)
 	delta := time.Now().Unix() - update.Unix()
-	if delta > int64(c.maxUpdateDelta.Seconds()) {
+	if delta > int64(maxDelta) {
 		w.WriteHeader(500)
-		_, _ = w.Write([]byte(fmt.Sprintf("error, %v > %v", delta, c.maxUpdateDelta.Seconds())))
-	} else {
+		_, _ = w.Write([]byte(fmt.Sprintf("error, %v > %v", delta, maxDelta)))
+		return
+	}
+	// Followers also fail when they cannot keep up with the writer
+	if c.followPath != "" {
+		lag := int64(c.FollowLag().Seconds())
+		if lag > int64(maxDelta) {
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte(fmt.Sprintf("error, follower lag %v > %v", lag, maxDelta)))
+			return
+		}
 		w.WriteHeader(200)
-		_, _ = w.Write([]byte(fmt.Sprintf("ok, %v < %v", delta, c.maxUpdateDelta.Seconds())))
+		_, _ = w.Write([]byte(fmt.Sprintf("ok, %v < %v, follower lag %v", delta, maxDelta, lag)))
+		return
 	}
+	w.WriteHeader(200)
+	_, _ = w.Write([]byte(fmt.Sprintf("ok, %v < %v", delta, maxDelta)))
 }
 
 func (c *ChainData) HandleCollators(w http.ResponseWriter, r *http.Request) {
-	stats := c.GetCollators()
-	handleJsonResponse(w, stats)
+	q, err := parseListQuery(r.URL.Query(), client.CollatorInfo{}, collatorIncludes)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid arguments: %v", err), 400)
+		return
+	}
+	data, status, err := c.collatorsAt(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	response, err := queryCollators(data, q)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	c.handleJsonResponse(w, r, &response.Info, response)
 }
 
 func (c *ChainData) HandleDelegations(w http.ResponseWriter, r *http.Request) {
 	p := strings.Split(r.URL.Path, "/")
 	if len(p) == 3 {
 		address := p[2]
+		q, err := parseListQuery(r.URL.Query(), DelegationInfo{}, delegationIncludes)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid arguments: %v", err), 400)
+			return
+		}
 		stats := c.GetDelegations(address)
 		if len(stats.Delegations) > 0 {
-			handleJsonResponse(w, stats)
+			response, err := queryDelegations(stats, c.selectedCollators(), q)
+			if err != nil {
+				http.Error(w, err.Error(), 400)
+				return
+			}
+			c.handleJsonResponse(w, r, &response.Info, response)
 			return
 		} else {
 			http.Error(w, fmt.Sprintf("Delegator '%v' not found", address), 404)
@@ -57,7 +141,7 @@ func (c *ChainData) HandleCollator(w http.ResponseWriter, r *http.Request) {
 		address := p[2]
 		stats := c.GetCollator(address)
 		if len(stats.Collators) > 0 {
-			handleJsonResponse(w, stats)
+			c.handleJsonResponse(w, r, &stats.Info, stats)
 			return
 		} else {
 			http.Error(w, fmt.Sprintf("Collator '%v' not found", address), 404)
@@ -68,11 +152,14 @@ func (c *ChainData) HandleCollator(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleJsonResponse(w http.ResponseWriter, data any) {
+func (c *ChainData) handleJsonResponse(w http.ResponseWriter, r *http.Request, info *ChainInfo, data any) {
 	// Cors
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	// Minimum caching
-	w.Header().Set("Cache-Control", "max-age:90, stale-if-error=600")
+	// Caching, unchanged data is not sent again
+	if c.setCacheHeaders(w, r, info) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	// Json
 	js, err := json.Marshal(data)
 	if err != nil {
@@ -88,68 +175,197 @@ func handleJsonResponse(w http.ResponseWriter, data any) {
 	}
 }
 
-func ServeChainData(config config.HttpConfig) {
-	chainData, err := NewChainData(
-		config.ChainConfig,
-		config.UpdateInterval*3,
-	)
-	if err != nil {
-		panic(err)
-	}
-	// First update, with cache check
-	shouldUpdateFromChain := true
-	if config.DataPath != "" {
-		err := chainData.UpdateFromJson(config.DataPath)
-		if err == nil {
-			shouldUpdateFromChain = false
-		}
-	}
-	if shouldUpdateFromChain {
-		retry := 0
-		for {
-			err = chainData.Update(28)
-			if err != nil {
-				if retry > 3 {
-					panic(err)
-				}
+// startUpdates loads the data path or updates from chain in background, retrying until the first update succeeds,
+// then updates every interval until quit is closed, the returned channel is closed when the background updates are over
+func (c *ChainData) startUpdates(dataPath string, quit chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// First update, with cache check
+		if dataPath == "" || c.UpdateFromJson(dataPath) != nil {
+			for c.Update(28) != nil {
 				log.Printf("Unable to update, waiting to retry...")
-				retry = retry + 1
-				time.Sleep(time.Second * 10)
-			} else {
-				if config.DataPath != "" {
-					_ = chainData.StoreToJson(config.DataPath)
+				select {
+				case <-time.After(startRetryDelay):
+				case <-quit:
+					return
 				}
-				break
+			}
+			if dataPath != "" {
+				_ = c.StoreToJson(dataPath)
 			}
 		}
-	}
-	// Start update routine
-	ticker := time.NewTicker(config.UpdateInterval)
-	quit := make(chan struct{})
-	go func() {
+		// Update routine, a store in progress is finished before leaving
+		timer := time.NewTimer(c.getUpdateInterval())
+		defer timer.Stop()
 		for {
 			select {
-			case <-ticker.C:
-				err = chainData.Update(28)
-				if err == nil && config.DataPath != "" {
-					_ = chainData.StoreToJson(config.DataPath)
+			case <-timer.C:
+				err := c.Update(28)
+				if err == nil && dataPath != "" {
+					_ = c.StoreToJson(dataPath)
+				}
+			case <-c.reconfigured:
+				log.Printf("Update interval is now %v", c.getUpdateInterval())
+				if !timer.Stop() {
+					<-timer.C
 				}
 			case <-quit:
-				ticker.Stop()
 				return
 			}
+			timer.Reset(c.getUpdateInterval())
 		}
 	}()
+	return done
+}
+
+// Handler returns the API routes of the chain data
+func (c *ChainData) Handler() http.Handler {
+	mux := http.NewServeMux()
 	// Live probes
-	http.HandleFunc("/healthz", chainData.HandleHealth)
+	mux.HandleFunc("/healthz", c.HandleHealth)
+	// Prometheus metrics
+	mux.Handle("/metrics", gziphandler.GzipHandler(http.HandlerFunc(c.HandleMetrics)))
 	// Generic info page with last update data
-	http.Handle("/info", gziphandler.GzipHandler(http.HandlerFunc(chainData.HandleInfo)))
+	mux.Handle("/info", gziphandler.GzipHandler(http.HandlerFunc(c.HandleInfo)))
 	// Main stats (for a collator or all)
-	http.Handle("/collators/", gziphandler.GzipHandler(http.HandlerFunc(chainData.HandleCollator)))
-	http.Handle("/collators", gziphandler.GzipHandler(http.HandlerFunc(chainData.HandleCollators)))
+	mux.Handle("/collators/", gziphandler.GzipHandler(http.HandlerFunc(c.HandleCollator)))
+	mux.Handle("/collators", gziphandler.GzipHandler(http.HandlerFunc(c.HandleCollators)))
+	// Archived rounds
+	mux.Handle("/rounds", gziphandler.GzipHandler(http.HandlerFunc(c.HandleRounds)))
+	// Update and round events, not compressed so that events are flushed as they come
+	mux.HandleFunc("/events", c.HandleEvents)
 	// Delegations
-	http.Handle("/delegations/", gziphandler.GzipHandler(http.HandlerFunc(chainData.HandleDelegations)))
+	mux.Handle("/delegations/", gziphandler.GzipHandler(http.HandlerFunc(c.HandleDelegations)))
+	return mux
+}
+
+// ServeChainData serves the networks until SIGINT or SIGTERM, on SIGHUP the optional reload function is called and
+// the reloadable settings of the new config are applied
+func ServeChainData(config config.HttpConfig, reload func() (config.HttpConfig, error)) {
+	// Signals are handled from the start, a shutdown during the first updates waits for them as for later ones
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	quit := make(chan struct{})
+	// Networks load in background, a network that cannot start is not served
+	networks := make([]*Network, 0, len(config.Networks))
+	for _, networkConfig := range config.Networks {
+		network, err := StartNetwork(config, networkConfig, quit)
+		if err != nil {
+			log.Printf("Unable to start %v, not serving it: %v", networkConfig.Name, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	if len(networks) == 0 {
+		panic(fmt.Errorf("no network could be started"))
+	}
 	// Start engine
-	log.Printf("Starting web server at %v", config.Addr)
-	log.Fatal(http.ListenAndServe(config.Addr, nil))
+	server := &http.Server{
+		Addr:              config.Addr,
+		Handler:           withTimeout(NetworksHandler(networks), config.WriteTimeout),
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+	// Event streams never become idle, they are ended so that shutdown can complete
+	server.RegisterOnShutdown(func() {
+		for _, network := range networks {
+			network.Data.events.close()
+		}
+	})
+	go func() {
+		log.Printf("Starting web server at %v", config.Addr)
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	for sig := range signals {
+		if sig != syscall.SIGHUP {
+			log.Printf("Received %v, shutting down", sig)
+			break
+		}
+		if reload == nil {
+			log.Printf("Received %v, nothing to reload", sig)
+			continue
+		}
+		newConfig, err := reload()
+		if err != nil {
+			log.Printf("Unable to reload configuration, keeping the current one: %v", err)
+			continue
+		}
+		reconfigure(networks, newConfig)
+	}
+	signal.Stop(signals)
+	// Drain requests, then wait for in-flight updates until the deadline
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if config.ShutdownTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Unable to drain requests: %v", err)
+	}
+	close(quit)
+	for _, network := range networks {
+		select {
+		case <-network.done:
+		case <-ctx.Done():
+			log.Printf("Abandoning %v update in progress", network.Name)
+		}
+		network.Close()
+	}
+	log.Printf("Shutdown complete")
+}
+
+// reconfigure applies a reloaded config to the running networks, networks cannot be added or removed
+func reconfigure(networks []*Network, config config.HttpConfig) {
+	for _, network := range networks {
+		found := false
+		for _, networkConfig := range config.Networks {
+			if networkConfig.Name == network.Name {
+				network.Reconfigure(config, networkConfig)
+				found = true
+			}
+		}
+		if !found {
+			log.Printf("Network %v is no longer configured, a restart is needed to remove it", network.Name)
+		}
+	}
+	if len(config.Networks) > len(networks) {
+		log.Printf("New networks are served after a restart")
+	}
+	log.Printf("Configuration reloaded")
+}
+
+// withTimeout limits the time spent handling requests, event streams are not limited
+func withTimeout(handler http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return handler
+	}
+	timed := http.TimeoutHandler(handler, timeout, "Request timeout")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		timed.ServeHTTP(w, r)
+	})
+}
+
+// NetworksHandler mounts each network under its name, a single network is also mounted at the root
+func NetworksHandler(networks []*Network) http.Handler {
+	mux := http.NewServeMux()
+	for _, network := range networks {
+		prefix := "/" + network.Name
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, network.Data.Handler()))
+	}
+	if len(networks) == 1 {
+		mux.Handle("/", networks[0].Data.Handler())
+	}
+	mux.Handle("/networks", gziphandler.GzipHandler(HandleNetworks(networks)))
+	return mux
 }
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(200)
+	})
+	handler := withTimeout(slow, 10*time.Millisecond)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/moonbeam/collators", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected timeout, got %v", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/moonbeam/events", nil))
+	if w.Code != 200 {
+		t.Errorf("expected event streams not to time out, got %v", w.Code)
+	}
+}
+
+func TestShutdownEndsEvents(t *testing.T) {
+	c, _ := NewChainData(config.ChainConfig{}, time.Minute, time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(c.HandleEvents))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	network := Network{Name: "moonbeam", Data: &c}
+	network.Close()
+	// The stream ends instead of waiting for the next event
+	ended := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			if _, err := resp.Body.Read(buf); err != nil {
+				close(ended)
+				return
+			}
+		}
+	}()
+	select {
+	case <-ended:
+	case <-time.After(5 * time.Second):
+		t.Errorf("event stream still open after close")
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	archive, err := NewArchive(t.TempDir(), config.ArchiveConfig{Rounds: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := NewChainData(config.ChainConfig{}, time.Minute, 3*time.Minute)
+	c.SetArchive(archive)
+	networks := []*Network{{Name: "moonbeam", Data: &c}}
+	reloaded := config.GetDefaultHttpConfig()
+	reloaded.UpdateInterval = 10 * time.Minute
+	reloaded.Archive.Rounds = 2
+	reloaded.Networks[0].ChainConfig.Endpoints = []string{"wss://example.com"}
+	reconfigure(networks, reloaded)
+	if c.getUpdateInterval() != 10*time.Minute || c.getMaxUpdateDelta() != 30*time.Minute {
+		t.Errorf("unexpected intervals %v %v", c.getUpdateInterval(), c.getMaxUpdateDelta())
+	}
+	if endpoints := c.getChainConfig().Endpoints; len(endpoints) != 1 || endpoints[0] != "wss://example.com" {
+		t.Errorf("unexpected endpoints %v", endpoints)
+	}
+	if archive.config.Rounds != 2 {
+		t.Errorf("expected archive retention to be reloaded")
+	}
+	select {
+	case <-c.reconfigured:
+	default:
+		t.Errorf("expected update loop to be notified")
+	}
+}
+
+func TestStartUpdatesQuit(t *testing.T) {
+	// Nothing listens on the endpoint so the first update keeps failing
+	c, _ := NewChainData(config.ChainConfig{Endpoints: []string{"ws://127.0.0.1:1"}}, time.Minute, time.Hour)
+	quit := make(chan struct{})
+	done := c.startUpdates("", quit)
+	close(quit)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("first update retries did not stop on quit")
+	}
+	if c.UpdateErrors() == 0 {
+		t.Errorf("expected failed updates to be counted")
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile is held by the writer of a data path
+const lockFile = ".lock"
+
+// LockDataPath takes the exclusive writer lock of a data path, the lock is held until the file is closed
+func LockDataPath(path string) (*os.File, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(path, lockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("data path %v is locked by another writer: %v", path, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,11 @@
+package server
+
+import (
+	"fmt"
+	"os"
+)
+
+// LockDataPath is not supported on windows, data paths cannot be shared there
+func LockDataPath(path string) (*os.File, error) {
+	return nil, fmt.Errorf("data path locking is not supported on windows")
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricsWriter writes metrics in the Prometheus text exposition format
+type metricsWriter struct {
+	b bytes.Buffer
+}
+
+func (m *metricsWriter) family(name string, kind string, help string) {
+	fmt.Fprintf(&m.b, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, kind)
+}
+
+// sample writes a value, labels are name and value pairs
+func (m *metricsWriter) sample(name string, value float64, labels ...string) {
+	m.b.WriteString(name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf("%v=\"%v\"", labels[i], escapeLabel(labels[i+1])))
+		}
+		fmt.Fprintf(&m.b, "{%v}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(&m.b, " %v\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (c *ChainData) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(c.metrics())
+}
+
+func (c *ChainData) metrics() []byte {
+	data := c.GetCollators()
+	info := data.Info
+	// Networks are told apart by the served name until the chain name is known
+	chain := info.Chain
+	if chain == "" {
+		chain = c.network
+	}
+	m := metricsWriter{}
+	// Chain state
+	m.family("mooncli_chain_block_number", "gauge", "Block number of the last update")
+	m.sample("mooncli_chain_block_number", float64(info.SnapBlock.Number), "chain", chain)
+	m.family("mooncli_chain_round", "gauge", "Round number of the last update")
+	m.sample("mooncli_chain_round", float64(info.SnapRound.Number), "chain", chain)
+	m.family("mooncli_chain_block_duration_seconds", "gauge", "Average block time")
+	m.sample("mooncli_chain_block_duration_seconds", info.SnapBlock.DurationSecs, "chain", chain)
+	m.family("mooncli_chain_selected_candidates", "gauge", "Size of the selected candidate set")
+	m.sample("mooncli_chain_selected_candidates", float64(info.SnapStaking.Selected), "chain", chain)
+	m.family("mooncli_chain_total_candidates", "gauge", "Size of the candidate pool")
+	m.sample("mooncli_chain_total_candidates", float64(info.SnapStaking.Total), "chain", chain)
+	// The selection threshold is the counted stake of the last selected candidate
+	threshold := math.Inf(1)
+	for i := range data.Collators {
+		if data.Collators[i].Selected {
+			threshold = math.Min(threshold, data.Collators[i].Counted.Float64())
+		}
+	}
+	if math.IsInf(threshold, 1) {
+		threshold = 0
+	}
+	revokeRound := info.SnapRound.Number + info.SnapRound.RevokeDelay
+	collatorMetrics := []struct {
+		name  string
+		help  string
+		value func(info *client.CollatorInfo) float64
+	}{
+		{"mooncli_collator_rank", "Rank in the candidate pool", func(info *client.CollatorInfo) float64 {
+			return float64(info.Rank)
+		}},
+		{"mooncli_collator_counted", "Counted stake", func(info *client.CollatorInfo) float64 {
+			return info.Counted.Float64()
+		}},
+		{"mooncli_collator_selected", "1 if the collator is in the selected set", func(info *client.CollatorInfo) float64 {
+			return boolValue(info.Selected)
+		}},
+		{"mooncli_collator_blocks", "Blocks produced in the current round", func(info *client.CollatorInfo) float64 {
+			return float64(info.Blocks)
+		}},
+		{"mooncli_collator_blocks_avg", "Average blocks per round across history rounds", func(info *client.CollatorInfo) float64 {
+			return float64(info.AverageBlocks())
+		}},
+		{"mooncli_collator_revoke_delta", "Counted stake leaving by the end of the revoke delay", func(info *client.CollatorInfo) float64 {
+			return info.Counted.Float64() - info.RevokeAt(revokeRound).Counted.Float64()
+		}},
+		{"mooncli_collator_threshold_distance", "Counted stake above the selection threshold, negative when below", func(info *client.CollatorInfo) float64 {
+			return info.Counted.Float64() - threshold
+		}},
+	}
+	for _, metric := range collatorMetrics {
+		m.family(metric.name, "gauge", metric.help)
+		for i := range data.Collators {
+			collator := &data.Collators[i]
+			m.sample(
+				metric.name,
+				metric.value(collator),
+				"chain", chain,
+				"address", strings.ToLower(collator.Address),
+				"display", collator.DisplayName(),
+			)
+		}
+	}
+	// Server internals
+	m.family("mooncli_last_update_timestamp_seconds", "gauge", "Unix time of the last successful update")
+	m.sample("mooncli_last_update_timestamp_seconds", info.Update.TsSecs, "chain", chain)
+	m.family("mooncli_update_duration_seconds", "gauge", "Duration of the last successful update")
+	m.sample("mooncli_update_duration_seconds", float64(info.Update.LenSecs), "chain", chain)
+	m.family("mooncli_update_errors_total", "counter", "Failed updates since start")
+	m.sample("mooncli_update_errors_total", float64(c.UpdateErrors()), "chain", chain)
+	calls := c.rpcStats.Calls()
+	methods := make([]string, 0, len(calls))
+	for method := range calls {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	m.family("mooncli_rpc_calls_total", "counter", "RPC calls of the chain updates by method")
+	for _, method := range methods {
+		m.sample("mooncli_rpc_calls_total", float64(calls[method].Calls), "chain", chain, "method", method)
+	}
+	m.family("mooncli_rpc_errors_total", "counter", "Failed RPC calls by method")
+	for _, method := range methods {
+		m.sample("mooncli_rpc_errors_total", float64(calls[method].Errors), "chain", chain, "method", method)
+	}
+	return m.b.Bytes()
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/internal/client"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleMetrics(t *testing.T) {
+	collators := testCollators(t, `[
+		{"address": "0xAA", "selected": true, "rank": 1, "blocks": 4, "counted": 3000, "display": "Zoo\"per"},
+		{"address": "0xbb", "selected": true, "rank": 2, "blocks": 2, "counted": 2000},
+		{"address": "0xcc", "selected": false, "rank": 3, "counted": 1500}
+	]`)
+	c := ChainData{
+		Info:      ChainInfo{Chain: "moonbeam", SnapRound: client.SnapRound{Number: 10}},
+		Collators: collators,
+	}
+	w := httptest.NewRecorder()
+	c.HandleMetrics(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %v", ct)
+	}
+	out := w.Body.String()
+	for _, expected := range []string{
+		"# TYPE mooncli_collator_rank gauge\n",
+		`mooncli_collator_rank{chain="moonbeam",address="0xaa",display="Zoo\"per"} 1`,
+		`mooncli_collator_selected{chain="moonbeam",address="0xcc",display="0xcc"} 0`,
+		`mooncli_collator_threshold_distance{chain="moonbeam",address="0xcc",display="0xcc"} -500`,
+		`mooncli_chain_round{chain="moonbeam"} 10`,
+		"# TYPE mooncli_update_errors_total counter\nmooncli_update_errors_total{chain=\"moonbeam\"} 0\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %v in\n%v", expected, out)
+		}
+	}
+	// Networks still loading are labelled with their name
+	loading := ChainData{network: "moonriver"}
+	if out := string(loading.metrics()); !strings.Contains(out, `mooncli_update_errors_total{chain="moonriver"} 0`) {
+		t.Errorf("expected network label in\n%v", out)
+	}
+}
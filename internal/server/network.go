@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/zooper-corp/mooncli/config"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Network is a chain served by the API under its name
+type Network struct {
+	Name string
+	Data *ChainData
+	// lock is held while the network owns its data path
+	lock *os.File
+	// done is closed when background updates are over
+	done <-chan struct{}
+}
+
+type NetworkInfo struct {
+	Name   string    `json:"name"`
+	Path   string    `json:"path"`
+	Info   ChainInfo `json:"info"`
+	Follow bool      `json:"follow,omitempty"`
+}
+
+// StartNetwork loads or follows the data of a network and starts its updates
+func StartNetwork(httpConfig config.HttpConfig, networkConfig config.NetworkConfig, quit chan struct{}) (*Network, error) {
+	chainData, err := NewChainData(
+		networkConfig.ChainConfig,
+		httpConfig.UpdateInterval,
+		httpConfig.UpdateInterval*3,
+	)
+	if err != nil {
+		return nil, err
+	}
+	chainData.network = networkConfig.Name
+	network := &Network{Name: networkConfig.Name, Data: &chainData}
+	// Followers read the writer data path, writers own theirs
+	dataPath := networkPath(httpConfig.DataPath, networkConfig.Name, len(httpConfig.Networks))
+	followPath := networkPath(httpConfig.FollowPath, networkConfig.Name, len(httpConfig.Networks))
+	if followPath == "" && dataPath != "" {
+		if network.lock, err = LockDataPath(dataPath); err != nil {
+			return nil, err
+		}
+	}
+	// Followers never create or change the archive of the writer
+	if followPath != "" && httpConfig.Archive.Rounds > 0 {
+		chainData.SetArchive(OpenArchive(followPath, httpConfig.Archive))
+	} else if followPath == "" && dataPath != "" && httpConfig.Archive.Rounds > 0 {
+		archive, err := NewArchive(dataPath, httpConfig.Archive)
+		if err != nil {
+			return nil, err
+		}
+		chainData.SetArchive(archive)
+	}
+	if followPath != "" {
+		log.Printf("Following %v data path %v", network.Name, followPath)
+		network.done = chainData.Follow(followPath, httpConfig.FollowInterval, quit)
+	} else {
+		network.done = chainData.startUpdates(dataPath, quit)
+	}
+	return network, nil
+}
+
+// Reconfigure applies the reloadable settings of a network
+func (n *Network) Reconfigure(httpConfig config.HttpConfig, networkConfig config.NetworkConfig) {
+	n.Data.Reconfigure(networkConfig.ChainConfig, httpConfig.UpdateInterval, httpConfig.UpdateInterval*3)
+	if n.Data.archive != nil {
+		n.Data.archive.SetConfig(httpConfig.Archive)
+	}
+}
+
+// Close ends event streams and releases the data path, background updates must be over or abandoned
+func (n *Network) Close() {
+	if n.Data.events != nil {
+		n.Data.events.close()
+	}
+	if n.lock != nil {
+		_ = n.lock.Close()
+	}
+}
+
+// networkPath returns the data path of a network, a sub folder when several networks share it
+func networkPath(path string, name string, networks int) string {
+	if path == "" || networks == 1 {
+		return path
+	}
+	return filepath.Join(path, name)
+}
+
+// HandleNetworks lists the served networks with their last update
+func HandleNetworks(networks []*Network) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := make([]NetworkInfo, 0, len(networks))
+		for _, network := range networks {
+			result = append(result, NetworkInfo{
+				Name:   network.Name,
+				Path:   "/" + network.Name,
+				Info:   *network.Data.GetInfo(),
+				Follow: network.Data.followPath != "",
+			})
+		}
+		js, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(js)
+	})
+}
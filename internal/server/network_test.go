@@ -0,0 +1,47 @@
+package server
+
+import (
+	"github.com/zooper-corp/mooncli/internal/client"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNetworksHandler(t *testing.T) {
+	networks := []*Network{
+		{Name: "moonbeam", Data: &ChainData{
+			Info:      ChainInfo{Chain: "Moonbeam"},
+			Collators: testCollators(t, `[{"address": "0xaa", "rank": 1}]`),
+		}},
+		{Name: "moonriver", Data: &ChainData{
+			Info:      ChainInfo{Chain: "Moonriver", SnapRound: client.SnapRound{Number: 7}},
+			Collators: testCollators(t, `[{"address": "0xbb", "rank": 1}]`),
+		}},
+	}
+	handler := NetworksHandler(networks)
+	for path, expected := range map[string]string{
+		"/moonbeam/collators/0xaa": `"address":"0xaa"`,
+		"/moonriver/info":          `"chain":"Moonriver"`,
+		"/networks":                `"path":"/moonriver"`,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != 200 || !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("expected %v for %v, got %v %v", expected, path, w.Code, w.Body.String())
+		}
+	}
+	// Root routes are only mounted for a single network
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/info", nil))
+	if w.Code != 404 {
+		t.Errorf("expected no root routes, got %v", w.Code)
+	}
+	w = httptest.NewRecorder()
+	NetworksHandler(networks[1:]).ServeHTTP(w, httptest.NewRequest("GET", "/collators/0xbb", nil))
+	if w.Code != 200 {
+		t.Errorf("expected root routes for a single network, got %v", w.Code)
+	}
+	if path := networkPath("/data", "moonbeam", 2); path != "/data/moonbeam" {
+		t.Errorf("unexpected network path %v", path)
+	}
+}
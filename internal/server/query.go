@@ -0,0 +1,274 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/filter"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// queryParameters documents the list query parameters, returned in the response metadata
+var queryParameters = map[string]string{
+	"limit":    "maximum number of items, 0 for all",
+	"offset":   "number of items to skip",
+	"sort":     "comma separated sort fields, prefix with '-' for descending order",
+	"selected": "true or false to only return selected or not selected collators",
+	"filter":   "collator filter expression, see the collators command help",
+	"fields":   "comma separated item fields to return, all fields if missing",
+	"include":  "comma separated optional parts to return, all parts if missing",
+}
+
+// collatorIncludes are the optional parts of a collator
+var collatorIncludes = []string{"history", "revokes"}
+
+// delegationIncludes are the optional parts of a delegation
+var delegationIncludes = []string{"revokes"}
+
+// delegationFields are the sort fields of a delegation
+var delegationFields = map[string]func(d *DelegationInfo) interface{}{
+	"collator":      func(d *DelegationInfo) interface{} { return d.Collator },
+	"address":       func(d *DelegationInfo) interface{} { return d.Address },
+	"amount":        func(d *DelegationInfo) interface{} { return d.Amount.Float64() },
+	"revoke_amount": func(d *DelegationInfo) interface{} { return d.RevokeAmount.Float64() },
+	"revoke_round":  func(d *DelegationInfo) interface{} { return d.RevokeRound },
+}
+
+// ListQuery holds the pagination, sorting and projection parameters of a list request
+type ListQuery struct {
+	Limit    int      `json:"limit,omitempty"`
+	Offset   int      `json:"offset"`
+	Sort     []string `json:"sort,omitempty"`
+	Selected *bool    `json:"selected,omitempty"`
+	Filter   string   `json:"filter,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	Include  []string `json:"include"`
+}
+
+// ListMeta describes the applied query, Total is the number of matching items before pagination
+type ListMeta struct {
+	ListQuery
+	Total      int               `json:"total"`
+	Count      int               `json:"count"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+type collatorsResponse struct {
+	Info      ChainInfo   `json:"info"`
+	Meta      ListMeta    `json:"meta"`
+	Collators interface{} `json:"collators"`
+}
+
+type delegationsResponse struct {
+	Info        ChainInfo   `json:"info"`
+	Meta        ListMeta    `json:"meta"`
+	Delegations interface{} `json:"delegations"`
+}
+
+// parseListQuery reads list parameters, fields and includes are checked against the given item and parts
+func parseListQuery(values url.Values, item interface{}, includes []string) (ListQuery, error) {
+	q := ListQuery{
+		Filter:  values.Get("filter"),
+		Sort:    splitList(values.Get("sort")),
+		Fields:  splitList(values.Get("fields")),
+		Include: includes,
+	}
+	var err error
+	for name, target := range map[string]*int{"limit": &q.Limit, "offset": &q.Offset} {
+		if v := values.Get(name); v != "" {
+			if *target, err = strconv.Atoi(v); err != nil || *target < 0 {
+				return q, fmt.Errorf("invalid %v '%v', expecting a positive number", name, v)
+			}
+		}
+	}
+	if v := values.Get("selected"); v != "" {
+		selected, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, fmt.Errorf("invalid selected '%v', expecting true or false", v)
+		}
+		q.Selected = &selected
+	}
+	available := jsonFields(item)
+	for _, field := range q.Fields {
+		if !contains(available, field) {
+			return q, fmt.Errorf("unknown field '%v', available fields are %v", field, available)
+		}
+	}
+	if values.Has("include") {
+		q.Include = splitList(values.Get("include"))
+		for _, include := range q.Include {
+			if !contains(includes, include) {
+				return q, fmt.Errorf("unknown include '%v', available parts are %v", include, includes)
+			}
+		}
+	}
+	return q, nil
+}
+
+// page returns the bounds of the requested page in a list of n items
+func (q ListQuery) page(n int) (int, int) {
+	start := q.Offset
+	if start > n {
+		start = n
+	}
+	end := n
+	if q.Limit > 0 && start+q.Limit < n {
+		end = start + q.Limit
+	}
+	return start, end
+}
+
+func (q ListQuery) meta(total int, count int) ListMeta {
+	return ListMeta{ListQuery: q, Total: total, Count: count, Parameters: queryParameters}
+}
+
+// queryCollators filters, sorts, pages and projects collators
+func queryCollators(data CollatorData, q ListQuery) (collatorsResponse, error) {
+	revokeRound := data.Info.SnapRound.Number + data.Info.SnapRound.RevokeDelay
+	collators, err := filter.Collators(data.Collators, q.Filter, revokeRound)
+	if err != nil {
+		return collatorsResponse{}, fmt.Errorf("invalid filter: %v", err)
+	}
+	// Copy before sorting, the pool is shared with other requests
+	result := make([]client.CollatorInfo, 0, len(collators))
+	for _, collator := range collators {
+		if q.Selected == nil || collator.Selected == *q.Selected {
+			if !contains(q.Include, "history") {
+				collator.History = nil
+			}
+			if !contains(q.Include, "revokes") {
+				collator.Revokes = nil
+			}
+			result = append(result, collator)
+		}
+	}
+	if err := filter.SortCollators(result, q.Sort, revokeRound); err != nil {
+		return collatorsResponse{}, err
+	}
+	start, end := q.page(len(result))
+	items, err := selectFields(result[start:end], q.Fields)
+	if err != nil {
+		return collatorsResponse{}, err
+	}
+	return collatorsResponse{
+		Info:      data.Info,
+		Meta:      q.meta(len(result), end-start),
+		Collators: items,
+	}, nil
+}
+
+// queryDelegations sorts, pages and projects delegations, selected applies to the delegated collator
+func queryDelegations(data DelegationData, selected map[string]bool, q ListQuery) (delegationsResponse, error) {
+	result := make([]DelegationInfo, 0, len(data.Delegations))
+	for _, delegation := range data.Delegations {
+		if q.Selected == nil || selected[strings.ToLower(delegation.Collator)] == *q.Selected {
+			if !contains(q.Include, "revokes") {
+				delegation.RevokeAmount = client.TokenBalance{}
+				delegation.RevokeReason = ""
+				delegation.RevokeRound = 0
+			}
+			result = append(result, delegation)
+		}
+	}
+	for _, key := range q.Sort {
+		if name, _ := filter.ParseSortKey(key); delegationFields[name] == nil {
+			return delegationsResponse{}, fmt.Errorf("unknown sort field '%v', available fields are %v", name, sortedKeys(delegationFields))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, key := range q.Sort {
+			name, desc := filter.ParseSortKey(key)
+			c := tools.CompareValues(delegationFields[name](&result[i]), delegationFields[name](&result[j]))
+			if c == 0 {
+				continue
+			}
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	start, end := q.page(len(result))
+	items, err := selectFields(result[start:end], q.Fields)
+	if err != nil {
+		return delegationsResponse{}, err
+	}
+	return delegationsResponse{
+		Info:        data.Info,
+		Meta:        q.meta(len(result), end-start),
+		Delegations: items,
+	}, nil
+}
+
+// selectFields returns the items unchanged without fields, otherwise objects with the given JSON fields only
+func selectFields[T any](items []T, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+	result := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		js, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(js, &all); err != nil {
+			return nil, err
+		}
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if v, ok := all[field]; ok {
+				selected[field] = v
+			}
+		}
+		result = append(result, selected)
+	}
+	return result, nil
+}
+
+// jsonFields returns the JSON names of the exported fields of a struct
+func jsonFields(item interface{}) []string {
+	t := reflect.TypeOf(item)
+	result := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func splitList(s string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestQueryCollators(t *testing.T) {
+	data := CollatorData{Collators: testCollators(t, `[
+		{"address": "0xaa", "display": "A", "rank": 1, "counted": 3000, "selected": true, "blocks": 1,
+			"history": {"9": {"rank": 1}}},
+		{"address": "0xbb", "display": "B", "rank": 2, "counted": 2000, "selected": true, "blocks": 4},
+		{"address": "0xcc", "display": "C", "rank": 3, "counted": 1000, "blocks": 2}
+	]`)}
+	values, _ := url.ParseQuery("selected=true&sort=-blocks&limit=1&offset=1&fields=address,rank,history")
+	q, err := parseListQuery(values, client.CollatorInfo{}, collatorIncludes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := queryCollators(data, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Meta.Total != 2 || response.Meta.Count != 1 || response.Meta.Parameters["limit"] == "" {
+		t.Errorf("unexpected meta %+v", response.Meta)
+	}
+	var items []map[string]interface{}
+	js, _ := json.Marshal(response.Collators)
+	if err := json.Unmarshal(js, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || len(items[0]) != 3 || items[0]["address"] != "0xaa" || items[0]["history"] == nil {
+		t.Errorf("unexpected collators %s", js)
+	}
+	// Optional parts are dropped unless included
+	values, _ = url.ParseQuery("include=revokes&limit=1")
+	q, _ = parseListQuery(values, client.CollatorInfo{}, collatorIncludes)
+	response, _ = queryCollators(data, q)
+	if js, _ := json.Marshal(response.Collators); strings.Contains(string(js), "history") {
+		t.Errorf("expected no history in %s", js)
+	}
+	if data.Collators[0].History == nil {
+		t.Errorf("query changed the pool")
+	}
+	for _, query := range []string{"limit=-1", "selected=maybe", "fields=stake", "include=delegations"} {
+		values, _ := url.ParseQuery(query)
+		if _, err := parseListQuery(values, client.CollatorInfo{}, collatorIncludes); err == nil {
+			t.Errorf("expected error for %v", query)
+		}
+	}
+	q = ListQuery{Sort: []string{"stake"}}
+	if _, err := queryCollators(data, q); err == nil {
+		t.Errorf("expected sort error")
+	}
+}
+
+func TestQueryDelegations(t *testing.T) {
+	var data DelegationData
+	testJson(t, `{"delegations": [
+		{"collator": "0xaa", "address": "0x01", "amount": 100, "revoke_amount": 100, "revoke_round": 12},
+		{"collator": "0xbb", "address": "0x01", "amount": 300},
+		{"collator": "0xcc", "address": "0x01", "amount": 200}
+	]}`, &data)
+	values, _ := url.ParseQuery("selected=true&sort=-amount&include=")
+	q, err := parseListQuery(values, DelegationInfo{}, delegationIncludes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := queryDelegations(data, map[string]bool{"0xaa": true, "0xbb": true}, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegations := response.Delegations.([]DelegationInfo)
+	if len(delegations) != 2 || delegations[0].Collator != "0xbb" || delegations[1].RevokeRound != 0 {
+		t.Errorf("unexpected delegations %+v", delegations)
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is the data path layout, version 0 had info.json and collators.json only and is loaded without
+// delegations, version 1 added derived.json and version 2 the manifest with checksums
+const cacheVersion = 2
+
+const (
+	manifestFile  = "manifest.json"
+	infoFile      = "info.json"
+	collatorsFile = "collators.json"
+	derivedFile   = "derived.json"
+)
+
+// dataFiles are the files of a data path, in write order
+var dataFiles = []string{infoFile, collatorsFile, derivedFile}
+
+// manifest lists the sha256 of each data file, it is written last
+type manifest struct {
+	Version int               `json:"version"`
+	Files   map[string]string `json:"files"`
+}
+
+// derivedData holds the data that is not part of the public collators JSON
+type derivedData struct {
+	Version int `json:"version"`
+	// Delegations are indexed by lower case collator address
+	Delegations map[string][]client.DelegatorState `json:"delegations"`
+}
+
+// writeDataFiles writes the data files and the manifest, each file is replaced atomically
+func writeDataFiles(path string, values map[string]interface{}) error {
+	m := manifest{Version: cacheVersion, Files: make(map[string]string)}
+	for _, name := range dataFiles {
+		b, err := json.MarshalIndent(values[name], "", " ")
+		if err != nil {
+			return fmt.Errorf("unable to encode %v: %v", name, err)
+		}
+		if err := writeFileAtomic(filepath.Join(path, name), b); err != nil {
+			return err
+		}
+		m.Files[name] = checksum(b)
+	}
+	b, err := json.MarshalIndent(m, "", " ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(path, manifestFile), b)
+}
+
+// readDataFiles reads and verifies the data files, returns the layout version they were written with
+func readDataFiles(path string) (map[string][]byte, int, error) {
+	files := make(map[string][]byte)
+	b, err := ioutil.ReadFile(filepath.Join(path, manifestFile))
+	if os.IsNotExist(err) {
+		return readLegacyDataFiles(path)
+	} else if err != nil {
+		return nil, 0, err
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, 0, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if m.Version > cacheVersion {
+		return nil, 0, fmt.Errorf("unsupported cache version %v, expecting %v", m.Version, cacheVersion)
+	}
+	for _, name := range dataFiles {
+		b, err := ioutil.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, 0, err
+		}
+		if sum := checksum(b); sum != m.Files[name] {
+			return nil, 0, fmt.Errorf("checksum mismatch for %v, expecting %v got %v", name, m.Files[name], sum)
+		}
+		files[name] = b
+	}
+	return files, m.Version, nil
+}
+
+// readLegacyDataFiles reads a data path written before the manifest, version 0 caches get empty derived data
+func readLegacyDataFiles(path string) (map[string][]byte, int, error) {
+	files := make(map[string][]byte)
+	for _, name := range dataFiles {
+		b, err := ioutil.ReadFile(filepath.Join(path, name))
+		if os.IsNotExist(err) && name == derivedFile {
+			files[name] = []byte(`{"version":0,"delegations":{}}`)
+			return files, 0, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		files[name] = b
+	}
+	var derived derivedData
+	if err := json.Unmarshal(files[derivedFile], &derived); err != nil {
+		return nil, 0, fmt.Errorf("invalid derived data: %v", err)
+	}
+	if derived.Version != 1 {
+		return nil, 0, fmt.Errorf("unsupported cache version %v without manifest", derived.Version)
+	}
+	return files, derived.Version, nil
+}
+
+// writeFileAtomic replaces a file through a synced temporary file so that readers never see partial content
+func writeFileAtomic(file string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"golang.org/x/exp/constraints"
 	"math"
+	"strings"
 )
 
 func Min[T constraints.Ordered](a, b T) T {
@@ -13,6 +14,13 @@ func Min[T constraints.Ordered](a, b T) T {
 	return b
 }
 
+func Max[T constraints.Ordered](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func Humanize(v float64) string {
 	switch {
 	case v > math.Pow10(6):
@@ -33,3 +41,45 @@ func Humanize(v float64) string {
 		return fmt.Sprintf("%.1f", v)
 	}
 }
+
+// CompareValues compares numbers numerically, bools false first and anything else as case-insensitive text
+func CompareValues(a interface{}, b interface{}) int {
+	fa, aok := ToFloat(a)
+	fb, bok := ToFloat(b)
+	if aok && bok {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprintf("%v", a)), strings.ToLower(fmt.Sprintf("%v", b)))
+}
+
+// ToFloat returns the value of numbers and bools, false for anything else
+func ToFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -27,3 +27,12 @@ func TestHumanize(t *testing.T) {
 		t.Errorf("Expected '0.123' got '%v'", h)
 	}
 }
+
+func TestCompareValues(t *testing.T) {
+	if CompareValues(uint32(2), 10.0) >= 0 || CompareValues(int64(3), uint64(3)) != 0 || CompareValues(true, false) <= 0 {
+		t.Errorf("Expected numeric comparison")
+	}
+	if CompareValues("Zooper", "alpha") <= 0 {
+		t.Errorf("Expected case insensitive comparison")
+	}
+}
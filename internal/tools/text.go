@@ -1,10 +1,19 @@
 package tools
 
 import (
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/text/unicode/norm"
 	"strings"
 	"unicode"
 )
 
+// transliterations for letters that do not decompose to an ASCII base letter
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'þ': "th", 'Þ': "Th",
+	'ı': "i", '‘': "'", '’': "'", '“': "\"", '”': "\"", '–': "-", '—': "-", '…': "...",
+}
+
 func ToAscii(s string) string {
 	t := strings.Map(func(r rune) rune {
 		if r > unicode.MaxASCII {
@@ -14,3 +23,77 @@ func ToAscii(s string) string {
 	}, s)
 	return t
 }
+
+// Transliterate converts a string to ASCII replacing accented letters with their base letter,
+// symbols without an ASCII equivalent are removed
+func Transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			continue
+		default:
+			if t, ok := transliterations[r]; ok {
+				b.WriteString(t)
+			} else if unicode.IsSpace(r) {
+				b.WriteRune(' ')
+			}
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// NormalizeWidth removes code points that terminals render inconsistently (variation selectors,
+// joiners, skin tone modifiers, bidi controls) so that the computed width matches what is displayed
+func NormalizeWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 0xFE00 && r <= 0xFE0F:
+			return -1
+		case r >= 0x1F3FB && r <= 0x1F3FF:
+			return -1
+		case r >= 0x200B && r <= 0x200F, r >= 0x202A && r <= 0x202E, r >= 0x2066 && r <= 0x2069:
+			return -1
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, norm.NFC.String(s))
+}
+
+// StringWidth returns the number of terminal cells used to display a string
+func StringWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runewidth.RuneWidth(r)
+	}
+	return w
+}
+
+// TruncateWidth truncates a string to fit the given terminal width appending tail if truncated
+func TruncateWidth(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	w := StringWidth(tail)
+	for _, r := range s {
+		rw := runewidth.RuneWidth(r)
+		if w+rw > width {
+			break
+		}
+		w += rw
+		b.WriteRune(r)
+	}
+	return b.String() + tail
+}
+
+// ShortAddress returns an abbreviated address as 0x1234...abcd
+func ShortAddress(address string) string {
+	if len(address) < 10 {
+		return address
+	}
+	return address[:6] + "..." + address[len(address)-4:]
+}
@@ -8,3 +8,43 @@ func TestToAscii(t *testing.T) {
 		t.Errorf("Expecting 'foobar' got '%v'", a)
 	}
 }
+
+func TestTransliterate(t *testing.T) {
+	a := Transliterate("Café Zürich Straße")
+	if a != "Cafe Zurich Strasse" {
+		t.Errorf("Expecting 'Cafe Zurich Strasse' got '%v'", a)
+	}
+	a = Transliterate("🛸 Zooper Corp 🛸")
+	if a != "Zooper Corp" {
+		t.Errorf("Expecting 'Zooper Corp' got '%v'", a)
+	}
+	a = Transliterate("月光")
+	if a != "" {
+		t.Errorf("Expecting '' got '%v'", a)
+	}
+}
+
+func TestNormalizeWidth(t *testing.T) {
+	a := NormalizeWidth("☀️ sun​")
+	if a != "☀ sun" {
+		t.Errorf("Expecting '☀ sun' got '%v'", a)
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	w := StringWidth("月光 ok")
+	if w != 7 {
+		t.Errorf("Expecting 7 got %v", w)
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	a := TruncateWidth("月光月光", 5, "~")
+	if a != "月光~" {
+		t.Errorf("Expecting '月光~' got '%v'", a)
+	}
+	a = TruncateWidth("moon", 5, "~")
+	if a != "moon" {
+		t.Errorf("Expecting 'moon' got '%v'", a)
+	}
+}
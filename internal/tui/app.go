@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/zooper-corp/mooncli/config"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/display"
+	"github.com/zooper-corp/mooncli/internal/filter"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"sort"
+	"strings"
+)
+
+const helpText = "[yellow]↑↓[-] move  [yellow]/[-] filter  [yellow]s[-] sort  [yellow]r[-] reverse  " +
+	"[yellow]tab[-] delegations  [yellow]enter[-] delegator  [yellow]esc[-] back  [yellow]q[-] quit"
+
+// maxDelegations is the number of top delegations shown in the detail pane
+const maxDelegations = 50
+
+// App is the full screen collator explorer
+type App struct {
+	model       *Model
+	options     config.TableOptions
+	app         *tview.Application
+	pages       *tview.Pages
+	header      *tview.TextView
+	list        *tview.Table
+	detail      *tview.TextView
+	delegations *tview.Table
+	delegator   *tview.Table
+	filter      *tview.InputField
+	footer      *tview.Flex
+	status      *tview.TextView
+	current     *client.CollatorInfo
+	jumps       []Delegation
+}
+
+// NewApp creates the explorer for a model, names are formatted with the table options
+func NewApp(model *Model, options config.TableOptions) *App {
+	a := &App{
+		model:       model,
+		options:     options,
+		app:         tview.NewApplication(),
+		pages:       tview.NewPages(),
+		header:      tview.NewTextView().SetDynamicColors(true),
+		list:        tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		detail:      tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		delegations: tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		delegator:   tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		filter:      tview.NewInputField().SetLabel("Filter: "),
+		status:      tview.NewTextView().SetDynamicColors(true),
+	}
+	a.list.SetBorder(true).SetTitle(" Collators ")
+	a.list.SetSelectionChangedFunc(func(row, _ int) {
+		a.selectCollator(row - 1)
+	})
+	a.delegations.SetBorder(true).SetTitle(" Top delegations ")
+	a.delegations.SetSelectedFunc(func(row, _ int) {
+		if a.current != nil && row > 0 && row <= len(a.current.Delegations) {
+			a.showDelegator(a.current.Delegations[row-1].Address)
+		}
+	})
+	a.delegator.SetBorder(true)
+	a.delegator.SetSelectedFunc(func(row, _ int) {
+		if row > 0 && row <= len(a.jumps) {
+			a.jumpTo(a.jumps[row-1].Collator.Address)
+		}
+	})
+	a.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			if err := a.model.SetFilter(a.filter.GetText()); err != nil {
+				a.setStatus(fmt.Sprintf("[red]%v[-]", tview.Escape(err.Error())))
+				return
+			}
+			a.refreshList("")
+		}
+		a.footer.RemoveItem(a.filter)
+		a.app.SetFocus(a.list)
+	})
+	detailPane := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.detail, 0, 3, false).
+		AddItem(a.delegations, 0, 2, false)
+	a.detail.SetBorder(true).SetTitle(" Details ")
+	body := tview.NewFlex().
+		AddItem(a.list, 0, 1, true).
+		AddItem(detailPane, 0, 1, false)
+	a.footer = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.status, 1, 0, false).
+		AddItem(tview.NewTextView().SetDynamicColors(true).SetText(helpText), 1, 0, false)
+	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.header, 1, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(a.footer, 2, 0, false)
+	a.pages.AddPage("main", main, true, true)
+	a.pages.AddPage("delegator", a.delegator, true, false)
+	a.header.SetText(fmt.Sprintf("[::b]%v[::-]", tview.Escape(model.Snapshot.Title())))
+	a.app.SetInputCapture(a.handleKey)
+	a.refreshList("")
+	return a
+}
+
+// Run starts the event loop until the user quits
+func (a *App) Run() error {
+	return a.app.SetRoot(a.pages, true).SetFocus(a.list).Run()
+}
+
+func (a *App) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	// Keys are left to the input field while typing a filter
+	if a.app.GetFocus() == a.filter {
+		return event
+	}
+	if name, _ := a.pages.GetFrontPage(); name == "delegator" {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			a.pages.SwitchToPage("main")
+			a.app.SetFocus(a.delegations)
+			return nil
+		}
+		return event
+	}
+	switch {
+	case event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab:
+		if a.app.GetFocus() == a.list {
+			a.app.SetFocus(a.delegations)
+		} else {
+			a.app.SetFocus(a.list)
+		}
+		return nil
+	case event.Key() == tcell.KeyEscape:
+		a.app.SetFocus(a.list)
+		return nil
+	case event.Rune() == 'q':
+		a.app.Stop()
+		return nil
+	case event.Rune() == '/':
+		a.filter.SetText(a.model.Filter)
+		a.footer.AddItem(a.filter, 1, 0, true)
+		a.app.SetFocus(a.filter)
+		return nil
+	case event.Rune() == 's':
+		a.model.NextSort()
+		a.refreshList(a.selectedAddress())
+		return nil
+	case event.Rune() == 'r':
+		a.model.ReverseSort()
+		a.refreshList(a.selectedAddress())
+		return nil
+	}
+	return event
+}
+
+func (a *App) setStatus(text string) {
+	a.status.SetText(text)
+}
+
+func (a *App) selectedAddress() string {
+	if a.current == nil {
+		return ""
+	}
+	return a.current.Address
+}
+
+// refreshList redraws the collator list keeping the selection on address when still visible
+func (a *App) refreshList(address string) {
+	a.list.Clear()
+	for i, header := range []string{"Rank", "Name", "Counted", "Blocks", "Avg", "Delta", "APR"} {
+		cell := tview.NewTableCell(header).SetSelectable(false).SetTextColor(tcell.ColorYellow)
+		if i == 1 {
+			cell.SetExpansion(1)
+		}
+		a.list.SetCell(0, i, cell)
+	}
+	for i, info := range a.model.Rows() {
+		color := tcell.ColorWhite
+		if !info.Selected {
+			color = tcell.ColorGray
+		}
+		delta, _ := collatorValue(info, a.model.RevokeRound, "revokes_delta")
+		values := []string{
+			fmt.Sprintf("%v", info.Rank),
+			display.CollatorName(info, a.options),
+			tools.Humanize(info.Counted.Float64()),
+			fmt.Sprintf("%v", info.Blocks),
+			fmt.Sprintf("%.1f", info.AverageBlocks()),
+			tools.Humanize(delta),
+			display.FormatApr(info.Apr),
+		}
+		for j, value := range values {
+			cell := tview.NewTableCell(tview.Escape(value)).SetTextColor(color)
+			if j != 1 {
+				cell.SetAlign(tview.AlignRight)
+			}
+			a.list.SetCell(i+1, j, cell)
+		}
+	}
+	a.setStatus(tview.Escape(a.model.Status()))
+	row := a.model.Index(address)
+	if row < 0 {
+		row = 0
+	}
+	if len(a.model.Rows()) > 0 {
+		a.list.Select(row+1, 0)
+		a.selectCollator(row)
+	} else {
+		a.selectCollator(-1)
+	}
+}
+
+// selectCollator shows the details of the collator at a list index
+func (a *App) selectCollator(index int) {
+	rows := a.model.Rows()
+	a.detail.Clear()
+	a.delegations.Clear()
+	if index < 0 || index >= len(rows) {
+		a.current = nil
+		return
+	}
+	a.current = rows[index]
+	a.detail.SetText(DetailText(a.current, a.model.RevokeRound, a.options))
+	a.detail.ScrollToBeginning()
+	for i, header := range []string{"Delegator", "Amount", "Revoke"} {
+		a.delegations.SetCell(0, i, tview.NewTableCell(header).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+	}
+	if len(a.current.Delegations) == 0 {
+		a.delegations.SetCell(1, 0, tview.NewTableCell("No delegations in snapshot").SetSelectable(false))
+		return
+	}
+	for i, d := range a.current.Delegations {
+		if i >= maxDelegations {
+			break
+		}
+		others := len(a.model.DelegationsOf(d.Address)) - 1
+		name := d.Address
+		if others > 0 {
+			name = fmt.Sprintf("%v (+%v)", name, others)
+		}
+		a.delegations.SetCell(i+1, 0, tview.NewTableCell(name).SetExpansion(1))
+		a.delegations.SetCell(i+1, 1, tview.NewTableCell(tools.Humanize(d.Amount.Float64())).SetAlign(tview.AlignRight))
+		a.delegations.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(revokeText(d))))
+	}
+	a.delegations.Select(1, 0).ScrollToBeginning()
+}
+
+// showDelegator lists all the delegations of a delegator
+func (a *App) showDelegator(address string) {
+	a.jumps = a.model.DelegationsOf(address)
+	a.delegator.Clear()
+	a.delegator.SetTitle(fmt.Sprintf(" Delegations of %v (enter to jump, esc to go back) ", address))
+	for i, header := range []string{"Collator", "Rank", "Selected", "Amount", "Revoke"} {
+		a.delegator.SetCell(0, i, tview.NewTableCell(header).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+	}
+	for i, d := range a.jumps {
+		selected := "no"
+		if d.Collator.Selected {
+			selected = "yes"
+		}
+		name := tview.Escape(display.CollatorName(d.Collator, a.options))
+		a.delegator.SetCell(i+1, 0, tview.NewTableCell(name).SetExpansion(1))
+		a.delegator.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%v", d.Collator.Rank)).SetAlign(tview.AlignRight))
+		a.delegator.SetCell(i+1, 2, tview.NewTableCell(selected))
+		a.delegator.SetCell(i+1, 3, tview.NewTableCell(tools.Humanize(d.State.Amount.Float64())).SetAlign(tview.AlignRight))
+		a.delegator.SetCell(i+1, 4, tview.NewTableCell(tview.Escape(revokeText(d.State))))
+	}
+	a.delegator.Select(1, 0)
+	a.pages.SwitchToPage("delegator")
+	a.app.SetFocus(a.delegator)
+}
+
+// jumpTo selects a collator in the list, the filter is cleared when it hides the collator
+func (a *App) jumpTo(address string) {
+	if a.model.Index(address) < 0 {
+		_ = a.model.SetFilter("")
+	}
+	a.pages.SwitchToPage("main")
+	a.refreshList(address)
+	a.app.SetFocus(a.list)
+}
+
+// DetailText describes a collator with history rounds and revoke timeline using tview color tags
+func DetailText(info *client.CollatorInfo, revokeRound uint32, options config.TableOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%v[::-]\n", tview.Escape(display.CollatorName(info, options)))
+	fmt.Fprintf(&b, "%v\n\n", info.Address)
+	status := "[gray]waiting[-]"
+	if info.Selected {
+		status = "[green]selected[-]"
+	}
+	fmt.Fprintf(&b, "Rank %v %v, counted %v, min bond %v, apr %v\n",
+		info.Rank, status, tools.Humanize(info.Counted.Float64()), tools.Humanize(info.MinBond.Float64()), display.FormatApr(info.Apr))
+	r := info.Reliability
+	fmt.Fprintf(&b, "Blocks %v this round, %.1f avg, produced %v/%.1f expected, zero rounds %v (current streak %v)\n",
+		info.Blocks, info.AverageBlocks(), r.Blocks, r.Expected, r.ZeroRounds, r.CurrentStreak)
+	if len(info.Orbiters) > 0 {
+		fmt.Fprintf(&b, "Orbiters %v\n", len(info.Orbiters))
+	}
+	// History, most recent first
+	if len(info.History) > 0 {
+		fmt.Fprintf(&b, "\n[yellow]%-8v %6v %6v %8v %10v[-]\n", "Round", "Rank", "Blocks", "Expected", "Counted")
+		for _, round := range sortedKeys(info.History, true) {
+			h := info.History[round]
+			color := "white"
+			if !h.Selected {
+				color = "gray"
+			} else if h.Blocks == 0 {
+				color = "red"
+			}
+			fmt.Fprintf(&b, "[%v]%-8v %6v %6v %8.1f %10v[-]\n",
+				color, round, h.Rank, h.Blocks, h.Expected, tools.Humanize(h.Counted.Float64()))
+		}
+	}
+	// Revoke timeline, oldest first
+	if len(info.Revokes) > 0 {
+		fmt.Fprintf(&b, "\n[yellow]%-8v %6v %10v %10v[-]\n", "Revoke", "Rank", "Counted", "Amount")
+		for _, round := range sortedKeys(info.Revokes, false) {
+			rr := info.Revokes[round]
+			color := "white"
+			if round == revokeRound {
+				color = "aqua"
+			}
+			fmt.Fprintf(&b, "[%v]%-8v %6v %10v %10v[-]\n",
+				color, round, rr.Rank, tools.Humanize(rr.Counted.Float64()), tools.Humanize(rr.Amount.Float64()))
+		}
+	}
+	return b.String()
+}
+
+func revokeText(d client.DelegatorState) string {
+	if d.RevokeRound == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v %v @%v", d.RevokeReason, tools.Humanize(d.RevokeAmount.Float64()), d.RevokeRound)
+}
+
+func collatorValue(info *client.CollatorInfo, revokeRound uint32, name string) (float64, bool) {
+	v, ok := filter.CollatorVars(info, revokeRound)(name)
+	if !ok {
+		return 0, false
+	}
+	return tools.ToFloat(v)
+}
+
+func sortedKeys[V any](m map[uint32]V, desc bool) []uint32 {
+	keys := make([]uint32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if desc {
+			return keys[i] > keys[j]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"github.com/zooper-corp/mooncli/internal/filter"
+	"github.com/zooper-corp/mooncli/internal/tools"
+	"sort"
+	"strings"
+)
+
+// SortKeys are the fields the collator list can be sorted by, in the order they are cycled
+var SortKeys = []string{"rank", "display", "counted", "blocks", "avg_blocks", "revokes_delta", "apr", "delegations"}
+
+// Delegation is a delegation of a delegator to a collator
+type Delegation struct {
+	Collator *client.CollatorInfo
+	State    client.DelegatorState
+}
+
+// Model holds the collator list state independently of the terminal
+type Model struct {
+	Snapshot    Snapshot
+	RevokeRound uint32
+	Filter      string
+	SortKey     string
+	SortDesc    bool
+	rows        []*client.CollatorInfo
+	delegators  map[string][]Delegation
+}
+
+// NewModel returns a model sorted by rank, revoke metrics are computed revokeRounds after the snapshot round
+func NewModel(s Snapshot, revokeRounds uint32) *Model {
+	m := &Model{
+		Snapshot:    s,
+		RevokeRound: s.Pool.RoundNumber + revokeRounds,
+		SortKey:     "rank",
+		delegators:  make(map[string][]Delegation),
+	}
+	for i := range m.Snapshot.Pool.Collators {
+		info := &m.Snapshot.Pool.Collators[i]
+		for _, d := range info.Delegations {
+			key := strings.ToLower(d.Address)
+			m.delegators[key] = append(m.delegators[key], Delegation{Collator: info, State: d})
+		}
+	}
+	_ = m.refresh()
+	return m
+}
+
+// Rows returns the collators matching the filter in sort order
+func (m *Model) Rows() []*client.CollatorInfo {
+	return m.rows
+}
+
+// SetFilter applies a filter expression, the previous filter is kept on error
+func (m *Model) SetFilter(s string) error {
+	if strings.TrimSpace(s) != "" {
+		if _, err := filter.ParseCollators(s); err != nil {
+			return err
+		}
+	}
+	m.Filter = strings.TrimSpace(s)
+	return m.refresh()
+}
+
+// SetSort sorts rows by a collator field
+func (m *Model) SetSort(key string, desc bool) error {
+	// A bare field is a valid expression, parsing checks the field exists
+	if _, err := filter.ParseCollators(key); err != nil {
+		return fmt.Errorf("invalid sort field: %v", err)
+	}
+	m.SortKey = key
+	m.SortDesc = desc
+	return m.refresh()
+}
+
+// NextSort moves to the next sort key, numeric metrics start in descending order
+func (m *Model) NextSort() {
+	next := SortKeys[0]
+	for i, key := range SortKeys {
+		if key == m.SortKey {
+			next = SortKeys[(i+1)%len(SortKeys)]
+		}
+	}
+	_ = m.SetSort(next, next != "rank" && next != "display")
+}
+
+// ReverseSort flips the sort order
+func (m *Model) ReverseSort() {
+	_ = m.SetSort(m.SortKey, !m.SortDesc)
+}
+
+// Index returns the row index of a collator, -1 if it is filtered out
+func (m *Model) Index(address string) int {
+	for i, info := range m.rows {
+		if strings.EqualFold(info.Address, address) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DelegationsOf returns all the delegations of a delegator, largest first
+func (m *Model) DelegationsOf(delegator string) []Delegation {
+	result := append([]Delegation{}, m.delegators[strings.ToLower(delegator)]...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].State.Amount.Float64() > result[j].State.Amount.Float64()
+	})
+	return result
+}
+
+// Status returns a short description of the current filter and sort
+func (m *Model) Status() string {
+	order := "asc"
+	if m.SortDesc {
+		order = "desc"
+	}
+	status := fmt.Sprintf("%v/%v collators, sort: %v %v", len(m.rows), len(m.Snapshot.Pool.Collators), m.SortKey, order)
+	if m.Filter != "" {
+		status = fmt.Sprintf("%v, filter: %v", status, m.Filter)
+	}
+	return status
+}
+
+func (m *Model) refresh() error {
+	collators, err := filter.Collators(m.Snapshot.Pool.Collators, m.Filter, m.RevokeRound)
+	if err != nil {
+		return err
+	}
+	// Filtering returns copies, rows point to the snapshot so that delegations can be linked back
+	rows := make([]*client.CollatorInfo, 0, len(collators))
+	for _, info := range collators {
+		for i := range m.Snapshot.Pool.Collators {
+			if m.Snapshot.Pool.Collators[i].Address == info.Address {
+				rows = append(rows, &m.Snapshot.Pool.Collators[i])
+				break
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, _ := filter.CollatorVars(rows[i], m.RevokeRound)(m.SortKey)
+		b, _ := filter.CollatorVars(rows[j], m.RevokeRound)(m.SortKey)
+		c := tools.CompareValues(a, b)
+		if m.SortDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+	m.rows = rows
+	return nil
+}
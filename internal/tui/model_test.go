@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"github.com/zooper-corp/mooncli/config"
+	"strings"
+	"testing"
+)
+
+const testSnapshot = `{
+ "version": 1,
+ "info": {"chain": "Moonbeam", "spec": 1502, "round": {"number": 10}, "token": {"decimals": 18, "symbol": "GLMR"}},
+ "collator_pool": {
+  "round_number": 10,
+  "collators": [
+   {"address": "0x01", "display": "Zooper", "selected": true, "rank": 1, "blocks": 3, "counted": 300,
+    "balance": {"free": 10, "reserved": 0, "frozen": 0},
+    "history": {"9": {"rank": 1, "blocks": 5, "selected": true}}},
+   {"address": "0x02", "display": "alpha", "selected": true, "rank": 2, "blocks": 7, "counted": 200,
+    "balance": {"free": 10, "reserved": 0, "frozen": 0}},
+   {"address": "0x03", "display": "Omega", "selected": false, "rank": 3, "blocks": 0, "counted": 100,
+    "balance": {"free": 10, "reserved": 0, "frozen": 0}}
+  ]
+ },
+ "delegations": {
+  "0x01": [{"address": "0xd1", "amount": 50}, {"address": "0xd2", "amount": 10}],
+  "0x02": [{"address": "0xD1", "amount": 80, "revoke_amount": 80, "revoke_reason": "revoke", "revoke_round": 12}]
+ }
+}`
+
+func testModel(t *testing.T) *Model {
+	s, err := ParseSnapshot([]byte(testSnapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewModel(s, 28)
+}
+
+func addresses(m *Model) string {
+	result := make([]string, 0)
+	for _, info := range m.Rows() {
+		result = append(result, info.Address)
+	}
+	return strings.Join(result, ",")
+}
+
+func TestParseSnapshot(t *testing.T) {
+	m := testModel(t)
+	if got := len(m.Snapshot.Pool.Collators[0].Delegations); got != 2 {
+		t.Fatalf("expected delegations to be restored, got %v", got)
+	}
+	if got := m.Snapshot.Pool.Collators[0].Counted.Float64(); got != 300 {
+		t.Errorf("got counted %v, wanted 300", got)
+	}
+	if _, err := ParseSnapshot([]byte(`{"version": 99}`)); err == nil {
+		t.Errorf("expected version error")
+	}
+	// The 'collators json' output has no version nor delegations
+	if _, err := ParseSnapshot([]byte(`{"info": {}, "collator_pool": {"collators": [{"address": "0x01"}]}}`)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestModelSortAndFilter(t *testing.T) {
+	m := testModel(t)
+	if got := addresses(m); got != "0x01,0x02,0x03" {
+		t.Errorf("got %v, wanted rank order", got)
+	}
+	m.NextSort()
+	if m.SortKey != "display" || addresses(m) != "0x02,0x03,0x01" {
+		t.Errorf("got %v by %v, wanted display order", addresses(m), m.SortKey)
+	}
+	m.ReverseSort()
+	if got := addresses(m); got != "0x01,0x03,0x02" {
+		t.Errorf("got %v, wanted reverse display order", got)
+	}
+	if err := m.SetSort("blocks", true); err != nil || addresses(m) != "0x02,0x01,0x03" {
+		t.Errorf("got %v %v, wanted blocks order", addresses(m), err)
+	}
+	if err := m.SetFilter("selected && counted < 250"); err != nil || addresses(m) != "0x02" {
+		t.Errorf("got %v %v, wanted filtered rows", addresses(m), err)
+	}
+	if err := m.SetFilter("stake > 1"); err == nil || m.Filter != "selected && counted < 250" {
+		t.Errorf("expected invalid filter to keep the previous one")
+	}
+	if m.Index("0x01") != -1 || m.Index("0x02") != 0 {
+		t.Errorf("unexpected index of filtered rows")
+	}
+	if err := m.SetSort("stake", false); err == nil {
+		t.Errorf("expected unknown sort field error")
+	}
+}
+
+func TestModelDelegationsOf(t *testing.T) {
+	m := testModel(t)
+	delegations := m.DelegationsOf("0xd1")
+	if len(delegations) != 2 {
+		t.Fatalf("got %v delegations, wanted 2", len(delegations))
+	}
+	if delegations[0].Collator.Address != "0x02" || delegations[0].State.RevokeRound != 12 {
+		t.Errorf("expected largest delegation first, got %+v", delegations[0])
+	}
+	if len(m.DelegationsOf("0xd3")) != 0 {
+		t.Errorf("expected no delegations for unknown delegator")
+	}
+}
+
+func TestDetailText(t *testing.T) {
+	m := testModel(t)
+	info := &m.Snapshot.Pool.Collators[0]
+	info.Apr = 0.1234
+	if text := DetailText(info, m.RevokeRound, config.GetDefaultTableOptions()); !strings.Contains(text, "apr 12.34%") {
+		t.Errorf("expected apr as a percentage in %v", text)
+	}
+}
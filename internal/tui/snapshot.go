@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zooper-corp/mooncli/internal/client"
+	"io/ioutil"
+	"strings"
+)
+
+// SnapshotVersion is the current snapshot layout, the output of 'collators json' is read as version 0
+const SnapshotVersion = 1
+
+// Snapshot is a collator pool saved for offline browsing, the layout extends the 'collators json' output
+// with delegations which are not part of the collator json
+type Snapshot struct {
+	Version     int                                `json:"version"`
+	Info        SnapshotInfo                       `json:"info"`
+	Pool        client.CollatorPool                `json:"collator_pool"`
+	Delegations map[string][]client.DelegatorState `json:"delegations,omitempty"`
+}
+
+type SnapshotInfo struct {
+	Chain       string           `json:"chain"`
+	SpecVersion int              `json:"spec"`
+	SnapBlock   client.SnapBlock `json:"block"`
+	SnapRound   client.SnapRound `json:"round"`
+	TokenInfo   client.TokenInfo `json:"token"`
+}
+
+// NewSnapshot returns a snapshot of a pool fetched by a client
+func NewSnapshot(pool client.CollatorPool, c *client.Client) Snapshot {
+	delegations := make(map[string][]client.DelegatorState)
+	for _, info := range pool.Collators {
+		if len(info.Delegations) > 0 {
+			delegations[strings.ToLower(info.Address)] = info.Delegations
+		}
+	}
+	return Snapshot{
+		Version: SnapshotVersion,
+		Info: SnapshotInfo{
+			Chain:       c.Chain,
+			SpecVersion: c.SpecVersion,
+			SnapBlock:   c.SnapBlock,
+			SnapRound:   c.SnapRound,
+			TokenInfo:   c.TokenInfo,
+		},
+		Pool:        pool,
+		Delegations: delegations,
+	}
+}
+
+// LoadSnapshot reads a snapshot from disk restoring collator delegations
+func LoadSnapshot(path string) (Snapshot, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return ParseSnapshot(b)
+}
+
+// ParseSnapshot decodes a snapshot, token info is read first as balances depend on it
+func ParseSnapshot(b []byte) (Snapshot, error) {
+	var header struct {
+		Version int          `json:"version"`
+		Info    SnapshotInfo `json:"info"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return Snapshot{}, err
+	}
+	if header.Version > SnapshotVersion {
+		return Snapshot{}, fmt.Errorf("unsupported snapshot version %v, expecting %v", header.Version, SnapshotVersion)
+	}
+	var s Snapshot
+	if err := client.UnmarshalJSON(b, &s, header.Info.TokenInfo); err != nil {
+		return Snapshot{}, err
+	}
+	if len(s.Pool.Collators) == 0 {
+		return Snapshot{}, fmt.Errorf("snapshot has no collators")
+	}
+	for i, info := range s.Pool.Collators {
+		s.Pool.Collators[i].Delegations = s.Delegations[strings.ToLower(info.Address)]
+	}
+	return s, nil
+}
+
+// Save writes the snapshot to disk
+func (s *Snapshot) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", " ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// Title returns the chain status line of the snapshot
+func (s *Snapshot) Title() string {
+	return fmt.Sprintf(
+		"Chain:%v runtime:%v round: %v block:#%v",
+		s.Info.Chain,
+		s.Info.SpecVersion,
+		s.Info.SnapRound.Number,
+		s.Info.SnapBlock.Number,
+	)
+}